@@ -0,0 +1,332 @@
+package quickhull
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// Feature is the kind of a feature of the surface of a hull.
+type Feature int
+
+const (
+	// FeatureVertex is a single vertex.
+	FeatureVertex Feature = iota
+	// FeatureEdge is the edge between two vertices.
+	FeatureEdge
+	// FeatureFace is the interior of a triangle (or of the polygon, for planar hulls).
+	FeatureFace
+)
+
+func (f Feature) String() string {
+	switch f {
+	case FeatureVertex:
+		return "vertex"
+	case FeatureEdge:
+		return "edge"
+	case FeatureFace:
+		return "face"
+	}
+	return fmt.Sprintf("Feature(%d)", int(f))
+}
+
+// SurfacePoint is the result of a closest point query.
+// Features refer to the triangles of the hull, so points on the diagonal of a flat quad are reported as edge points.
+type SurfacePoint struct {
+	Point    r3.Vector // Closest point on the surface of the hull
+	Distance float64   // Distance to Point, negative if the query point is inside the hull
+	Feature  Feature   // Feature that Point lies on
+	Vertices []int     // Vertex indices of the feature: one for vertices, two for edges, three for faces or the outline of planar hulls
+	Face     int       // Index of the triangle (ConvexHull) or Face (HalfEdgeMesh) containing Point, -1 if the hull isn't a polytope
+}
+
+// ClosestPoint returns the point on the surface of the hull that is closest to p.
+// The search walks from face to face: for points outside the hull it only visits faces that have p on their outer side,
+// points inside the hull are projected onto the nearest face and its neighbors in the same plane. Faces within Epsilon are visited too.
+// Hulls built by the caller are searched by checking all faces instead. Hulls that aren't polytopes are handled like the point,
+// segment or polygon they are. Returns a SurfacePoint with an infinite Distance for empty hulls.
+func (hull ConvexHull) ClosestPoint(p r3.Vector) SurfacePoint {
+	if hull.Dimension < DimensionPolytope {
+		return closestPointOfOutline(p, hull.Vertices, hull.Outline, hull.Polygon)
+	}
+	if hull.derived == nil {
+		// There is nowhere to keep the adjacency, building it for every query costs more than checking all faces
+		return closestPointOfAll(hull, p, planeDistance(hull.Planes(), p) <= 0)
+	}
+	return closestPoint(hull, p, hull.Epsilon)
+}
+
+// ClosestPoint returns the point on the surface of the mesh that is closest to p, see ConvexHull.ClosestPoint.
+func (mesh HalfEdgeMesh) ClosestPoint(p r3.Vector) SurfacePoint {
+	if mesh.Dimension < DimensionPolytope {
+		var poly *Polygon
+		if mesh.Dimension == DimensionPolygon {
+			_, poly = newPolygon(mesh.Vertices, mesh.Outline, true)
+		}
+		return closestPointOfOutline(p, mesh.Vertices, mesh.Outline, poly)
+	}
+	return closestPoint(mesh, p, mesh.Epsilon)
+}
+
+// Closed triangle mesh of a convex polytope.
+type triangleMesh interface {
+	triangleCount() int
+	triangle(i int) [3]int  // Vertex indices
+	neighbor(i, j int) int  // Triangle sharing the edge from corner j to corner j+1 of triangle i
+	plane(i int) Plane      // Plane of the triangle, its normal points out of the hull
+	vertex(v int) r3.Vector // Position of a vertex
+}
+
+func (hull ConvexHull) triangleCount() int {
+	return len(hull.Indices) / 3
+}
+
+func (hull ConvexHull) triangle(i int) [3]int {
+	return [3]int{hull.Indices[3*i], hull.Indices[3*i+1], hull.Indices[3*i+2]}
+}
+
+func (hull ConvexHull) neighbor(i, j int) int {
+	return hull.triangleAdjacency()[i][j]
+}
+
+func (hull ConvexHull) plane(i int) Plane {
+	return hull.Planes()[i]
+}
+
+func (hull ConvexHull) vertex(v int) r3.Vector {
+	return hull.Vertices[v]
+}
+
+// Returns the half edges of the Face, the first one ends at the first vertex.
+func (mesh HalfEdgeMesh) halfEdgesOfFace(i int) [3]int {
+	he0 := mesh.Faces[i].HalfEdge
+	he1 := mesh.HalfEdges[he0].Next
+	return [3]int{he0, he1, mesh.HalfEdges[he1].Next}
+}
+
+func (mesh HalfEdgeMesh) triangleCount() int {
+	return len(mesh.Faces)
+}
+
+func (mesh HalfEdgeMesh) triangle(i int) [3]int {
+	var v [3]int
+	for j, he := range mesh.halfEdgesOfFace(i) {
+		v[j] = mesh.HalfEdges[he].EndVertex
+	}
+	return v
+}
+
+func (mesh HalfEdgeMesh) neighbor(i, j int) int {
+	// The half edge from corner j to corner j+1 ends at corner j+1
+	he := mesh.halfEdgesOfFace(i)[(j+1)%3]
+	return mesh.HalfEdges[mesh.HalfEdges[he].Opp].Face
+}
+
+func (mesh HalfEdgeMesh) plane(i int) Plane {
+	v := mesh.triangle(i)
+	return newFacePlane(mesh.Vertices[v[0]], mesh.Vertices[v[1]], mesh.Vertices[v[2]])
+}
+
+func (mesh HalfEdgeMesh) vertex(v int) r3.Vector {
+	return mesh.Vertices[v]
+}
+
+// Returns the neighbors of the triangles, computed on first use.
+func (hull ConvexHull) triangleAdjacency() [][3]int {
+	if hull.derived == nil {
+		return triangleAdjacency(hull.Indices)
+	}
+	hull.derived.adjacencyOnce.Do(func() {
+		hull.derived.adjacency = triangleAdjacency(hull.Indices)
+	})
+	return hull.derived.adjacency
+}
+
+// Returns the neighbors of the triangles: adjacency[i][j] is the triangle sharing the edge from corner j to corner j+1 of triangle i.
+func triangleAdjacency(indices []int) [][3]int {
+	edges := make(map[[2]int]int, len(indices))
+	for i, v := range indices {
+		edges[[2]int{v, indices[i-i%3+(i+1)%3]}] = i / 3
+	}
+
+	adjacency := make([][3]int, len(indices)/3)
+	for i, v := range indices {
+		n, found := edges[[2]int{indices[i-i%3+(i+1)%3], v}]
+		if !found {
+			n = -1
+		}
+		adjacency[i/3][i%3] = n
+	}
+	return adjacency
+}
+
+// Returns the closest point of the surface of a triangle mesh by walking over its triangles.
+func closestPoint(m triangleMesh, p r3.Vector, epsilon float64) SurfacePoint {
+	n := m.triangleCount()
+	if n == 0 {
+		return SurfacePoint{Distance: math.Inf(1), Face: -1}
+	}
+
+	// Hill-climb towards a face that has p on its outer side
+	best, bestD := 0, m.plane(0).SignedDistance(p)
+	for bestD <= 0 {
+		next := -1
+		for j := 0; j < 3; j++ {
+			if nb := m.neighbor(best, j); nb >= 0 {
+				if d := m.plane(nb).SignedDistance(p); d > bestD {
+					next, bestD = nb, d
+				}
+			}
+		}
+		if next < 0 {
+			break
+		}
+		best = next
+	}
+	if bestD <= 0 {
+		// Local maximum, p might still be outside. Otherwise this finds the nearest plane, which may not be adjacent at all
+		for i := 0; i < n; i++ {
+			if d := m.plane(i).SignedDistance(p); d > bestD {
+				best, bestD = i, d
+			}
+		}
+	}
+	inside := bestD <= 0
+
+	// Outside, the closest point is on one of the faces that have p on their outer side, which are connected.
+	// Inside, it's on the nearest plane, whose faces are connected and not farther from p than any point found so far.
+	// Faces within epsilon of these are included, as they may hold the closest point of p near the surface or of nearly coplanar faces.
+	result := SurfacePoint{Distance: math.Inf(1)}
+	visited := map[int]bool{best: true}
+	stack := []int{best}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		closestPointOfTriangle(m, i, p, &result)
+		for j := 0; j < 3; j++ {
+			nb := m.neighbor(i, j)
+			if nb < 0 || visited[nb] {
+				continue
+			}
+			d := m.plane(nb).SignedDistance(p)
+			if d > -epsilon || inside && -d <= result.Distance+epsilon {
+				visited[nb] = true
+				stack = append(stack, nb)
+			}
+		}
+	}
+
+	if inside {
+		result.Distance = -result.Distance
+	}
+	return result
+}
+
+// Returns the closest point of the surface of a triangle mesh by checking all triangles, inside determines the sign of the distance.
+func closestPointOfAll(m triangleMesh, p r3.Vector, inside bool) SurfacePoint {
+	result := SurfacePoint{Distance: math.Inf(1), Face: -1}
+	for i := 0; i < m.triangleCount(); i++ {
+		closestPointOfTriangle(m, i, p, &result)
+	}
+
+	if inside && result.Face >= 0 {
+		result.Distance = -result.Distance
+	}
+	return result
+}
+
+// Replaces result with the point of triangle i closest to p if that is closer.
+func closestPointOfTriangle(m triangleMesh, i int, p r3.Vector, result *SurfacePoint) {
+	v := m.triangle(i)
+	q, corners := closestPointOnTriangle(p, m.vertex(v[0]), m.vertex(v[1]), m.vertex(v[2]))
+	if d := q.Sub(p).Norm(); d < result.Distance {
+		*result = SurfacePoint{Point: q, Distance: d, Face: i}
+		for j := range v {
+			if corners&(1<<uint(j)) != 0 {
+				result.Vertices = append(result.Vertices, v[j])
+			}
+		}
+		result.Feature = Feature(len(result.Vertices) - 1)
+	}
+}
+
+// Returns the point of the triangle closest to p and the corners spanning the feature it lies on as bit mask (1 for a, 2 for b, 4 for c).
+// See Ericson's "Real-Time Collision Detection", section 5.1.5.
+func closestPointOnTriangle(p, a, b, c r3.Vector) (r3.Vector, uint) {
+	ab, ac, ap := b.Sub(a), c.Sub(a), p.Sub(a)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a, 1
+	}
+
+	bp := p.Sub(b)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b, 2
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return a.Add(ab.Mul(d1 / (d1 - d3))), 1 | 2
+	}
+
+	cp := p.Sub(c)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c, 4
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return a.Add(ac.Mul(d2 / (d2 - d6))), 1 | 4
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return b.Add(c.Sub(b).Mul((d4 - d3) / ((d4 - d3) + (d5 - d6)))), 2 | 4
+	}
+
+	denom := 1 / (va + vb + vc)
+	return a.Add(ab.Mul(vb * denom)).Add(ac.Mul(vc * denom)), 1 | 2 | 4
+}
+
+// Returns the closest point of a hull that isn't a polytope: a single point, a segment or a polygon.
+func closestPointOfOutline(p r3.Vector, vertices []r3.Vector, outline []int, poly *Polygon) SurfacePoint {
+	switch {
+	case len(outline) == 0:
+		return SurfacePoint{Distance: math.Inf(1), Face: -1}
+
+	case len(outline) == 1:
+		v := vertices[outline[0]]
+		return SurfacePoint{Point: v, Distance: v.Sub(p).Norm(), Feature: FeatureVertex, Vertices: []int{outline[0]}, Face: -1}
+
+	case poly != nil:
+		if h, inside := poly.project(p); inside {
+			q := p.Sub(poly.Normal.Mul(h))
+			return SurfacePoint{Point: q, Distance: math.Abs(h), Feature: FeatureFace, Vertices: append([]int(nil), outline...), Face: -1}
+		}
+	}
+
+	// Closest point on the boundary
+	result := SurfacePoint{Distance: math.Inf(1), Face: -1}
+	edges := len(outline)
+	if edges == 2 {
+		// A segment has a single edge
+		edges = 1
+	}
+	for i := 0; i < edges; i++ {
+		ia, ib := outline[i], outline[(i+1)%len(outline)]
+		a, b := vertices[ia], vertices[ib]
+		q := closestPointOnSegment(p, a, b)
+		if d := q.Sub(p).Norm(); d < result.Distance {
+			result = SurfacePoint{Point: q, Distance: d, Feature: FeatureEdge, Vertices: []int{ia, ib}, Face: -1}
+			if q == a {
+				result.Feature, result.Vertices = FeatureVertex, []int{ia}
+			} else if q == b {
+				result.Feature, result.Vertices = FeatureVertex, []int{ib}
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package quickhull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestClosestPointCube(t *testing.T) {
+	hull := convexHull(cubePointCloud(1))
+
+	sp := hull.ClosestPoint(r3.Vector{X: 2, Y: 0.3, Z: 0.1})
+	assertEqual(t, FeatureFace, sp.Feature)
+	assertEqual(t, 3, len(sp.Vertices))
+	assertAlmostEqual(t, 1, sp.Distance, 1e-12)
+	assertAlmostEqual(t, 0, sp.Point.Sub(r3.Vector{X: 1, Y: 0.3, Z: 0.1}).Norm(), 1e-12)
+	assertAlmostEqual(t, 1, hull.Planes()[sp.Face].Normal.X, 1e-12)
+
+	sp = hull.ClosestPoint(r3.Vector{X: 2, Y: 2, Z: 0.5})
+	assertEqual(t, FeatureEdge, sp.Feature)
+	assertAlmostEqual(t, math.Sqrt2, sp.Distance, 1e-12)
+	assertAlmostEqual(t, 0, sp.Point.Sub(r3.Vector{X: 1, Y: 1, Z: 0.5}).Norm(), 1e-12)
+	for _, v := range sp.Vertices {
+		assertEqual(t, 1.0, hull.Vertices[v].X)
+		assertEqual(t, 1.0, hull.Vertices[v].Y)
+	}
+
+	sp = hull.ClosestPoint(r3.Vector{X: 2, Y: 2, Z: 2})
+	assertEqual(t, FeatureVertex, sp.Feature)
+	assertEqual(t, r3.Vector{X: 1, Y: 1, Z: 1}, hull.Vertices[sp.Vertices[0]])
+	assertAlmostEqual(t, math.Sqrt(3), sp.Distance, 1e-12)
+
+	sp = hull.ClosestPoint(r3.Vector{X: 0.1, Y: 0.5, Z: 0.2})
+	assertAlmostEqual(t, -0.5, sp.Distance, 1e-12)
+	assertAlmostEqual(t, 0, sp.Point.Sub(r3.Vector{X: 0.1, Y: 1, Z: 0.2}).Norm(), 1e-12)
+
+	// SignedDistance is exact outside of the hull
+	assertAlmostEqual(t, math.Sqrt2, hull.SignedDistance(r3.Vector{X: 2, Y: 2}), 1e-12)
+	assertEqual(t, "edge", FeatureEdge.String())
+}
+
+func TestClosestPointRandom(t *testing.T) {
+	pointCloud := randomPointCloud(300)
+	hull := convexHull(pointCloud)
+	mesh := new(QuickHull).ConvexHullAsMesh(pointCloud, 0)
+
+	for _, p := range randomPointCloud(500) {
+		p = p.Mul(2)
+
+		// Brute force
+		expected := math.Inf(1)
+		for _, tri := range hull.Triangles() {
+			q, _ := closestPointOnTriangle(p, tri[0], tri[1], tri[2])
+			expected = math.Min(expected, q.Sub(p).Norm())
+		}
+		if hull.SignedDistance(p) < 0 {
+			expected = -expected
+		}
+
+		sp := hull.ClosestPoint(p)
+		assertAlmostEqual(t, expected, sp.Distance, 1e-12)
+		assertAlmostEqual(t, math.Abs(sp.Distance), sp.Point.Sub(p).Norm(), 1e-12)
+		assertAlmostEqual(t, 0, hull.Planes()[sp.Face].SignedDistance(sp.Point), 1e-12)
+
+		msp := mesh.ClosestPoint(p)
+		assertAlmostEqual(t, expected, msp.Distance, 1e-12)
+		var expectedVertices, actualVertices []r3.Vector
+		for _, v := range sp.Vertices {
+			expectedVertices = append(expectedVertices, hull.Vertices[v])
+		}
+		for _, v := range msp.Vertices {
+			actualVertices = append(actualVertices, mesh.Vertices[v])
+		}
+		assertElementsMatch(t, expectedVertices, actualVertices)
+	}
+}
+
+func TestClosestPointInside(t *testing.T) {
+	// The faces on top aren't adjacent to the faces at the bottom, which have the nearest plane of points below the center
+	var pointCloud []r3.Vector
+	for _, v := range cubePointCloud(1) {
+		pointCloud = append(pointCloud, r3.Vector{X: 5 * v.X, Y: 5 * v.Y, Z: v.Z})
+	}
+	hull := convexHull(pointCloud)
+	mesh := new(QuickHull).ConvexHullAsMesh(pointCloud, 0)
+	assertEqual(t, hull.Epsilon, mesh.Epsilon)
+
+	for _, c := range []struct {
+		p, closest r3.Vector
+	}{
+		{r3.Vector{X: 1, Y: 2, Z: 0.6}, r3.Vector{X: 1, Y: 2, Z: 1}},
+		{r3.Vector{X: -3, Y: 1, Z: -0.7}, r3.Vector{X: -3, Y: 1, Z: -1}},
+		{r3.Vector{X: 4.9, Y: -2, Z: 0.5}, r3.Vector{X: 5, Y: -2, Z: 0.5}},
+	} {
+		expected := -c.closest.Sub(c.p).Norm()
+		for _, sp := range []SurfacePoint{hull.ClosestPoint(c.p), mesh.ClosestPoint(c.p)} {
+			assertAlmostEqual(t, expected, sp.Distance, 1e-12)
+			assertAlmostEqual(t, 0, sp.Point.Sub(c.closest).Norm(), 1e-12)
+		}
+	}
+}
+
+func TestClosestPointCallerBuilt(t *testing.T) {
+	built := convexHull(randomPointCloud(100))
+	hull := ConvexHull{Vertices: built.Vertices, Indices: built.Indices, Dimension: DimensionPolytope}
+
+	for _, p := range randomPointCloud(100) {
+		p = p.Mul(2)
+		assertEqual(t, built.ClosestPoint(p).Distance, hull.ClosestPoint(p).Distance)
+		assertEqual(t, built.SignedDistance(p), hull.SignedDistance(p))
+	}
+}
+
+func TestClosestPointBoundary(t *testing.T) {
+	// Subsets of a lattice, points on the surface lie on the planes of many faces up to rounding errors
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 200; i++ {
+		var pointCloud []r3.Vector
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				for z := 0; z < 5; z++ {
+					if r.Intn(2) > 0 {
+						pointCloud = append(pointCloud, r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)})
+					}
+				}
+			}
+		}
+
+		hull := convexHull(pointCloud)
+		for _, p := range pointCloud {
+			if d := hull.ClosestPoint(p).Distance; d > 1e-12 {
+				t.Fatalf("point %v of the input is %v outside of the hull", p, d)
+			}
+		}
+	}
+}
+
+func TestClosestPointDegenerate(t *testing.T) {
+	square := []r3.Vector{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0.5, Y: 0.5}}
+	hull := convexHull(square)
+	mesh := new(QuickHull).ConvexHullAsMesh(square, 0)
+
+	for _, sp := range []SurfacePoint{hull.ClosestPoint(r3.Vector{X: 0.5, Y: 0.25, Z: -2}), mesh.ClosestPoint(r3.Vector{X: 0.5, Y: 0.25, Z: -2})} {
+		assertEqual(t, FeatureFace, sp.Feature)
+		assertEqual(t, -1, sp.Face)
+		assertEqual(t, 4, len(sp.Vertices))
+		assertAlmostEqual(t, 2, sp.Distance, 1e-12)
+		assertAlmostEqual(t, 0, sp.Point.Sub(r3.Vector{X: 0.5, Y: 0.25}).Norm(), 1e-12)
+	}
+
+	sp := hull.ClosestPoint(r3.Vector{X: 2, Y: 0.5})
+	assertEqual(t, FeatureEdge, sp.Feature)
+	assertAlmostEqual(t, 1, sp.Distance, 1e-12)
+
+	sp = hull.ClosestPoint(r3.Vector{X: 2, Y: 2})
+	assertEqual(t, FeatureVertex, sp.Feature)
+	assertEqual(t, r3.Vector{X: 1, Y: 1}, hull.Vertices[sp.Vertices[0]])
+
+	segment := convexHull([]r3.Vector{{X: 0}, {X: 1}, {X: 0.5}})
+	sp = segment.ClosestPoint(r3.Vector{X: 0.5, Y: 1})
+	assertEqual(t, FeatureEdge, sp.Feature)
+	assertEqual(t, r3.Vector{X: 0.5}, sp.Point)
+	sp = segment.ClosestPoint(r3.Vector{X: -1, Y: 1})
+	assertEqual(t, FeatureVertex, sp.Feature)
+	assertEqual(t, r3.Vector{}, sp.Point)
+
+	sp = ConvexHull{}.ClosestPoint(r3.Vector{})
+	assertEqual(t, math.Inf(1), sp.Distance)
+}
@@ -0,0 +1,200 @@
+package quickhull
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+)
+
+// Plane is the plane of a face of a ConvexHull.
+type Plane struct {
+	Normal r3.Vector // Unit normal, pointing out of the hull
+	Offset float64   // Normal.Dot(p) for points p on the plane
+}
+
+// SignedDistance returns the distance of p to the plane, positive on the side Normal points to.
+func (p Plane) SignedDistance(q r3.Vector) float64 {
+	return p.Normal.Dot(q) - p.Offset
+}
+
+// Returns the plane of a triangle with its right-hand normal.
+func newFacePlane(a, b, c r3.Vector) Plane {
+	n := triangleNormal(a, b, c).Normalize()
+	return Plane{Normal: n, Offset: (n.Dot(a) + n.Dot(b) + n.Dot(c)) / 3}
+}
+
+// Planes returns the plane of each triangle of a polytope, the normals point out of the hull.
+// A planar hull has no inside, its planes are the plane of the Polygon and the same plane facing the other side.
+// Hulls of points and segments have no planes.
+// The planes are computed from Vertices and Indices on first use, so hulls built by the caller work too if their Dimension is set.
+func (hull ConvexHull) Planes() []Plane {
+	if hull.derived == nil {
+		return hull.computePlanes()
+	}
+	hull.derived.planesOnce.Do(func() {
+		hull.derived.planes = hull.computePlanes()
+	})
+	return hull.derived.planes
+}
+
+func (hull ConvexHull) computePlanes() []Plane {
+	switch hull.Dimension {
+	case DimensionPolytope:
+	case DimensionPolygon:
+		pl := Plane{Normal: hull.Polygon.Normal, Offset: hull.Polygon.Offset}
+		return []Plane{pl, {Normal: pl.Normal.Mul(-1), Offset: -pl.Offset}}
+	default:
+		return nil
+	}
+
+	// Any point inside the hull tells which way the normals have to point, regardless of the winding
+	var centroid r3.Vector
+	for _, idx := range hull.Indices {
+		centroid = centroid.Add(hull.Vertices[idx])
+	}
+	centroid = centroid.Mul(1 / float64(len(hull.Indices)))
+
+	planes := make([]Plane, 0, len(hull.Indices)/3)
+	for i := 0; i+2 < len(hull.Indices); i += 3 {
+		pl := newFacePlane(hull.Vertices[hull.Indices[i]], hull.Vertices[hull.Indices[i+1]], hull.Vertices[hull.Indices[i+2]])
+		if pl.SignedDistance(centroid) > 0 {
+			pl = Plane{Normal: pl.Normal.Mul(-1), Offset: -pl.Offset}
+		}
+		planes = append(planes, pl)
+	}
+	return planes
+}
+
+// Location describes where a point is relative to a ConvexHull.
+type Location int
+
+const (
+	// LocationInside means the point is inside the hull by more than ConvexHull.Epsilon.
+	LocationInside Location = iota
+	// LocationBoundary means the point is within ConvexHull.Epsilon of the surface of the hull.
+	LocationBoundary
+	// LocationOutside means the point is outside the hull by more than ConvexHull.Epsilon.
+	LocationOutside
+)
+
+func (l Location) String() string {
+	switch l {
+	case LocationInside:
+		return "inside"
+	case LocationBoundary:
+		return "boundary"
+	case LocationOutside:
+		return "outside"
+	}
+	return fmt.Sprintf("Location(%d)", int(l))
+}
+
+// Below this number of points, spreading queries over multiple goroutines isn't worth it.
+const parallelQueryThreshold = 1024
+
+// SignedDistance returns the signed distance of p to the hull: negative inside, positive outside.
+// Inside the hull this is the (negated) distance to the nearest face, outside it's the distance to the closest point of the
+// surface (see ClosestPoint). Hulls that aren't polytopes have no inside, the result is the distance to the point, segment or polygon.
+// Returns +Inf for empty hulls.
+func (hull ConvexHull) SignedDistance(p r3.Vector) float64 {
+	if hull.Dimension == DimensionPolytope {
+		if d := planeDistance(hull.Planes(), p); d <= 0 {
+			return d
+		}
+		// The farthest plane is only a lower bound of the distance, e.g. near edges
+	}
+
+	return hull.ClosestPoint(p).Distance
+}
+
+// Locate reports whether p is inside, outside or on the boundary of the hull, using Epsilon as tolerance.
+// For polytopes only the distances to the planes of the faces are checked.
+// Points are never inside hulls that aren't polytopes.
+func (hull ConvexHull) Locate(p r3.Vector) Location {
+	if hull.Dimension == DimensionPolytope {
+		return hull.location(planeDistance(hull.Planes(), p))
+	}
+	return hull.location(hull.SignedDistance(p))
+}
+
+// Contains reports whether p is inside or on the boundary of the hull, see Locate.
+func (hull ConvexHull) Contains(p r3.Vector) bool {
+	return hull.Locate(p) != LocationOutside
+}
+
+// SignedDistances returns SignedDistance for each of the points, spread over the given number of goroutines.
+func (hull ConvexHull) SignedDistances(points []r3.Vector, workers int) []float64 {
+	hull = hull.cached()
+	distances := make([]float64, len(points))
+	hull.query(len(points), workers, func(i int) {
+		distances[i] = hull.SignedDistance(points[i])
+	})
+	return distances
+}
+
+// LocateAll returns Locate for each of the points, spread over the given number of goroutines.
+func (hull ConvexHull) LocateAll(points []r3.Vector, workers int) []Location {
+	hull = hull.cached()
+	locations := make([]Location, len(points))
+	hull.query(len(points), workers, func(i int) {
+		locations[i] = hull.Locate(points[i])
+	})
+	return locations
+}
+
+// Returns the largest signed distance of p to the planes.
+func planeDistance(planes []Plane, p r3.Vector) float64 {
+	d := math.Inf(-1)
+	for _, pl := range planes {
+		d = math.Max(d, pl.SignedDistance(p))
+	}
+	return d
+}
+
+func (hull ConvexHull) location(d float64) Location {
+	if d > hull.Epsilon {
+		return LocationOutside
+	}
+	if d < -hull.Epsilon {
+		return LocationInside
+	}
+	return LocationBoundary
+}
+
+// Returns the hull with a cache for the derived data, so it's computed only once for all queries of hulls built by the caller.
+func (hull ConvexHull) cached() ConvexHull {
+	if hull.derived == nil {
+		hull.derived = new(derivedData)
+	}
+	return hull
+}
+
+// Calls query for 0 <= i < n, using multiple goroutines if there are enough queries.
+func (hull ConvexHull) query(n int, workers int, query func(i int)) {
+	if n < parallelQueryThreshold {
+		workers = 1
+	}
+	parallelFor(n, workers, func(from, to int) {
+		for i := from; i < to; i++ {
+			query(i)
+		}
+	})
+}
+
+// Returns the signed distance of p to the plane of the polygon and whether the projection of p onto the plane is inside the polygon.
+func (poly *Polygon) project(p r3.Vector) (float64, bool) {
+	q := r2.Point{X: p.Dot(poly.U), Y: p.Dot(poly.V)}
+
+	// The projection is inside the polygon if it's on the same side of all edges, regardless of the winding
+	positive, negative := false, false
+	for i, a := range poly.Points {
+		b := poly.Points[(i+1)%len(poly.Points)]
+		c := b.Sub(a).Cross(q.Sub(a))
+		positive = positive || c > 0
+		negative = negative || c < 0
+	}
+
+	return poly.Normal.Dot(p) - poly.Offset, !positive || !negative
+}
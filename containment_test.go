@@ -0,0 +1,114 @@
+package quickhull
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestConvexHullPlanes(t *testing.T) {
+	pointCloud := randomPointCloud(500)
+	hull := convexHull(pointCloud)
+
+	assertEqual(t, len(hull.Indices)/3, len(hull.Planes()))
+	for i, pl := range hull.Planes() {
+		assertAlmostEqual(t, 1, pl.Normal.Norm(), 1e-12)
+		for _, v := range hull.Triangles()[i] {
+			assertAlmostEqual(t, 0, pl.SignedDistance(v), 1e-12)
+		}
+	}
+
+	for _, p := range pointCloud {
+		if hull.SignedDistance(p) > hull.Epsilon {
+			t.Fatalf("point %v is outside of the hull", p)
+		}
+		assertEqual(t, true, hull.Contains(p))
+	}
+}
+
+func TestConvexHullSignedDistance(t *testing.T) {
+	hull := convexHull(cubePointCloud(1))
+
+	assertAlmostEqual(t, -1, hull.SignedDistance(r3.Vector{}), 1e-12)
+	assertAlmostEqual(t, -0.5, hull.SignedDistance(r3.Vector{X: 0.5, Y: 0.2}), 1e-12)
+	assertAlmostEqual(t, 1, hull.SignedDistance(r3.Vector{X: 2}), 1e-12)
+	assertAlmostEqual(t, 0, hull.SignedDistance(r3.Vector{X: 1, Y: 0.3, Z: -0.7}), 1e-12)
+}
+
+func TestConvexHullLocate(t *testing.T) {
+	hull := convexHull(cubePointCloud(1))
+	assertEqual(t, 1e-7, hull.Epsilon)
+
+	assertEqual(t, LocationInside, hull.Locate(r3.Vector{X: 0.9}))
+	assertEqual(t, LocationBoundary, hull.Locate(r3.Vector{X: 1}))
+	assertEqual(t, LocationBoundary, hull.Locate(r3.Vector{X: 1 + 1e-8, Y: 1, Z: -1}))
+	assertEqual(t, LocationOutside, hull.Locate(r3.Vector{X: 1.1}))
+	assertEqual(t, true, hull.Contains(r3.Vector{X: 1}))
+	assertEqual(t, false, hull.Contains(r3.Vector{X: 1.1}))
+	assertEqual(t, "boundary", LocationBoundary.String())
+
+	// Epsilon is in the units of the input
+	hull, err := new(QuickHull).TryConvexHull(cubePointCloud(1000), Options{Epsilon: 1e-3, EpsilonMode: AbsoluteEpsilon, Normalize: true})
+	assertEqual(t, nil, err)
+	assertAlmostEqual(t, 1e-3, hull.Epsilon, 1e-15)
+	assertEqual(t, LocationBoundary, hull.Locate(r3.Vector{X: 1000.0005}))
+	assertEqual(t, LocationOutside, hull.Locate(r3.Vector{X: 1000.002}))
+}
+
+func TestConvexHullLocateAll(t *testing.T) {
+	hull := convexHull(randomPointCloud(200))
+	queries := randomPointCloud(5000)
+
+	locations := hull.LocateAll(queries, 4)
+	distances := hull.SignedDistances(queries, 4)
+	assertEqual(t, len(queries), len(locations))
+	for i, p := range queries {
+		assertEqual(t, hull.Locate(p), locations[i])
+		assertEqual(t, hull.SignedDistance(p), distances[i])
+	}
+}
+
+func TestConvexHullLocateCallerBuilt(t *testing.T) {
+	built := convexHull(cubePointCloud(1))
+
+	for _, winding := range [][3]int{{0, 1, 2}, {0, 2, 1}} {
+		hull := ConvexHull{Vertices: built.Vertices, Dimension: DimensionPolytope, Epsilon: 1e-7}
+		for i := 0; i < len(built.Indices); i += 3 {
+			for _, k := range winding {
+				hull.Indices = append(hull.Indices, built.Indices[i+k])
+			}
+		}
+
+		assertEqual(t, built.Planes(), hull.Planes())
+		assertAlmostEqual(t, -1, hull.SignedDistance(r3.Vector{}), 1e-12)
+		assertEqual(t, []Location{LocationInside, LocationBoundary, LocationOutside},
+			hull.LocateAll([]r3.Vector{{X: 0.5}, {X: 1}, {X: 1.5}}, 1))
+	}
+}
+
+func TestConvexHullLocateDegenerate(t *testing.T) {
+	square := []r3.Vector{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0.5, Y: 0.5}}
+	hull := convexHull(square)
+
+	assertEqual(t, DimensionPolygon, hull.Dimension)
+	assertAlmostEqual(t, 2, hull.SignedDistance(r3.Vector{X: 0.5, Y: 0.5, Z: 2}), 1e-12)
+	assertAlmostEqual(t, 1, hull.SignedDistance(r3.Vector{X: 2, Y: 0.5}), 1e-12)
+	assertAlmostEqual(t, math.Sqrt(3), hull.SignedDistance(r3.Vector{X: 2, Y: 2, Z: 1}), 1e-12)
+	assertEqual(t, LocationBoundary, hull.Locate(r3.Vector{X: 0.2, Y: 0.7}))
+	assertEqual(t, []Plane{{Normal: r3.Vector{Z: 1}}, {Normal: r3.Vector{Z: -1}}}, hull.Planes())
+	assertEqual(t, LocationOutside, hull.Locate(r3.Vector{X: 0.2, Y: 0.7, Z: 0.1}))
+
+	segment := convexHull([]r3.Vector{{X: 0}, {X: 1}, {X: 0.5}})
+	assertEqual(t, DimensionSegment, segment.Dimension)
+	assertAlmostEqual(t, 1, segment.SignedDistance(r3.Vector{X: 0.5, Y: 1}), 1e-12)
+	assertAlmostEqual(t, 2, segment.SignedDistance(r3.Vector{X: -2}), 1e-12)
+	assertEqual(t, true, segment.Contains(r3.Vector{X: 0.25}))
+
+	assertEqual(t, 0, len(segment.Planes()))
+
+	point := convexHull([]r3.Vector{{X: 1, Y: 2, Z: 3}, {X: 1, Y: 2, Z: 3}})
+	assertEqual(t, DimensionPoint, point.Dimension)
+	assertAlmostEqual(t, 3, point.SignedDistance(r3.Vector{X: 1, Y: 2}), 1e-12)
+	assertEqual(t, true, point.Contains(r3.Vector{X: 1, Y: 2, Z: 3}))
+}
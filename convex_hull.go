@@ -1,6 +1,8 @@
 package quickhull
 
 import (
+	"sync"
+
 	"github.com/golang/geo/r3"
 )
 
@@ -8,6 +10,28 @@ type ConvexHull struct {
 	optimizedVertexBuffer []r3.Vector
 	Vertices              []r3.Vector
 	Indices               []int
+	Normals               []r3.Vector // Unit normal (pointing out of the hull) of each triangle, only set if Options.ComputeNormals is true
+	Epsilon               float64     // Tolerance used during the construction in units of the input, see Locate
+	Diagnostics           Diagnostics // Statistics about the construction of the hull
+
+	// Dimension of the point cloud. Hulls of points and segments have no triangles (except for QuickHull.ConvexHull,
+	// which returns the triangles of a degenerate tetrahedron for compatibility), planar hulls are triangulated.
+	Dimension Dimension
+	// Vertex indices of hulls that aren't polytopes: the point, the endpoints of the segment or the ordered boundary of the polygon.
+	// The boundary follows Options.Winding when viewed from the side Polygon.Normal points to.
+	Outline []int
+	// The convex polygon, only set if Dimension is DimensionPolygon.
+	Polygon *Polygon
+
+	derived *derivedData // Data computed from the triangles on first use, nil for hulls built by the caller
+}
+
+// Data derived from the triangles of a ConvexHull. It's shared by the copies of the hull.
+type derivedData struct {
+	planesOnce    sync.Once
+	planes        []Plane
+	adjacencyOnce sync.Once
+	adjacency     [][3]int // Neighbors of the triangles, see triangleAdjacency
 }
 
 func (hull ConvexHull) Triangles() [][3]r3.Vector {
@@ -20,8 +44,25 @@ func (hull ConvexHull) Triangles() [][3]r3.Vector {
 	return triangles
 }
 
-func newConvexHull(mesh meshBuilder, pointCloud []r3.Vector, ccw bool, useOriginalIndices bool) ConvexHull {
-	var hull ConvexHull
+func newConvexHull(mesh meshBuilder, pointCloud []r3.Vector, opts Options, dim Dimension, outline []int) ConvexHull {
+	hull := ConvexHull{Dimension: dim, derived: new(derivedData)}
+
+	ccw := opts.Winding == CounterClockwise
+	useOriginalIndices := opts.IndexMode == OriginalIndices
+
+	if dim < DimensionPolygon && !opts.legacy {
+		// No triangles, just the point or the endpoints of the segment
+		if useOriginalIndices {
+			hull.Vertices = pointCloud
+			hull.Outline = append([]int(nil), outline...)
+		} else {
+			for i, v := range outline {
+				hull.Vertices = append(hull.Vertices, pointCloud[v])
+				hull.Outline = append(hull.Outline, i)
+			}
+		}
+		return hull
+	}
 
 	faceProcessed := make([]bool, len(mesh.faces))
 	var faceStack []int
@@ -59,6 +100,10 @@ func newConvexHull(mesh meshBuilder, pointCloud []r3.Vector, ccw bool, useOrigin
 		}
 
 		vertices := mesh.vertexIndicesOfFace(topFace)
+		if opts.ComputeNormals {
+			hull.Normals = append(hull.Normals, triangleNormal(pointCloud[vertices[0]], pointCloud[vertices[1]], pointCloud[vertices[2]]).Normalize())
+		}
+
 		if !useOriginalIndices {
 			for i, v := range vertices {
 				it, found := vertexIndexMapping[v]
@@ -89,5 +134,22 @@ func newConvexHull(mesh meshBuilder, pointCloud []r3.Vector, ccw bool, useOrigin
 		hull.Vertices = hull.optimizedVertexBuffer
 	}
 
+	if dim == DimensionPolygon {
+		outline, hull.Polygon = newPolygon(pointCloud, outline, ccw)
+	}
+
+	for _, v := range outline {
+		if !useOriginalIndices {
+			var found bool
+			v, found = vertexIndexMapping[v]
+			assertTrue(found)
+		}
+		hull.Outline = append(hull.Outline, v)
+	}
+
+	if hull.Polygon != nil {
+		hull.Polygon.Indices = hull.Outline
+	}
+
 	return hull
 }
@@ -0,0 +1,70 @@
+package quickhull
+
+import (
+	"context"
+
+	"github.com/golang/geo/r3"
+)
+
+// Directions in which the extreme points of the point cloud span the culling polytope.
+var cullingDirections = []r3.Vector{
+	{X: 1}, {X: -1}, {Y: 1}, {Y: -1}, {Z: 1}, {Z: -1},
+	{X: 1, Y: 1, Z: 1}, {X: 1, Y: 1, Z: -1}, {X: 1, Y: -1, Z: 1}, {X: 1, Y: -1, Z: -1},
+	{X: -1, Y: 1, Z: 1}, {X: -1, Y: 1, Z: -1}, {X: -1, Y: -1, Z: 1}, {X: -1, Y: -1, Z: -1},
+}
+
+// Returns the indices of the points of vertexData that are not strictly inside the polytope spanned by the extreme points
+// in the axis and diagonal directions (Akl–Toussaint heuristic). Points strictly inside can't be vertices of the hull.
+// Returns nil if the polytope has no volume, in which case nothing can be culled.
+// qh.epsilon must be set up when this is called.
+func (qh *QuickHull) cullInteriorPoints(ctx context.Context) ([]int, error) {
+	var extremes [14]int
+	var maxDots [14]float64
+	for i, d := range cullingDirections {
+		maxDots[i] = d.Dot(qh.vertexData[0])
+	}
+	for i, v := range qh.vertexData[1:] {
+		for j, d := range cullingDirections {
+			if dot := d.Dot(v); dot > maxDots[j] {
+				maxDots[j] = dot
+				extremes[j] = i + 1
+			}
+		}
+		if err := checkContext(ctx, i); err != nil {
+			return nil, err
+		}
+	}
+
+	extremePoints := make([]r3.Vector, len(extremes))
+	for i, idx := range extremes {
+		extremePoints[i] = qh.vertexData[idx]
+	}
+
+	var polytope QuickHull
+	err := polytope.buildMesh(context.Background(), extremePoints, Options{Epsilon: qh.epsilon, EpsilonMode: AbsoluteEpsilon})
+	if err != nil || polytope.dimension < DimensionPolytope {
+		return nil, nil
+	}
+
+	var planes []plane
+	for _, f := range polytope.mesh.faces {
+		if !f.isDisabled() {
+			planes = append(planes, f.plane)
+		}
+	}
+
+	kept := make([]int, 0, len(extremes))
+	for i, v := range qh.vertexData {
+		for _, p := range planes {
+			if d := signedDistanceToPlane(v, p); d >= 0 || d*d <= qh.epsilonSquared*p.sqrNLength {
+				kept = append(kept, i)
+				break
+			}
+		}
+		if err := checkContext(ctx, i); err != nil {
+			return nil, err
+		}
+	}
+
+	return kept, nil
+}
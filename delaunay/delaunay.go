@@ -0,0 +1,88 @@
+// Package delaunay computes Delaunay triangulations of two dimensional point sets.
+//
+// The points are lifted onto the paraboloid z = x² + y², the lower faces of the convex hull of the lifted points
+// (computed with quickhull) project to the Delaunay triangles.
+package delaunay
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+	"github.com/markus-wa/quickhull-go/v2/internal/paraboloid"
+)
+
+// Triangulate computes the Delaunay triangulation of the given points.
+// The returned triangles are counter-clockwise and refer to the indices of the points.
+// If epsilon is <= 0 a default value will be used, it's relative to the extent of the points.
+//
+// Points that (nearly) coincide with other points are only used once. If more than three points lie on a common circle,
+// which makes the triangulation ambiguous, any of the possible triangulations is returned.
+// Returns an error of kind quickhull.KindDegenerateInput if the points are empty or all lie on a line.
+func Triangulate(points []r2.Point, epsilon float64) ([][3]int, error) {
+	if len(points) == 0 {
+		return nil, &quickhull.Error{Kind: quickhull.KindDegenerateInput, Msg: "point set is empty"}
+	}
+	if epsilon <= 0 {
+		epsilon = quickhull.DefaultEpsilon
+	}
+
+	t, err := paraboloid.NewTransform(points)
+	if err != nil {
+		return nil, err
+	}
+	lifted := t.Lift(points, nil)
+
+	// With CounterClockwise winding the right-hand normals point into the hull, so lower faces are counter-clockwise when viewed from above.
+	// Lifted grids and other cocircular points produce lots of coplanar faces, robust predicates make sure none of the points get lost.
+	hull, err := new(quickhull.QuickHull).TryConvexHull(lifted, quickhull.Options{
+		Epsilon:        epsilon,
+		Winding:        quickhull.CounterClockwise,
+		IndexMode:      quickhull.OriginalIndices,
+		ComputeNormals: true,
+		Robust:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch hull.Dimension {
+	case quickhull.DimensionPolytope:
+		var triangles [][3]int
+		for i, n := range hull.Normals {
+			if n.Z < -epsilon {
+				triangles = append(triangles, [3]int{hull.Indices[3*i], hull.Indices[3*i+1], hull.Indices[3*i+2]})
+			}
+		}
+		return triangles, nil
+
+	case quickhull.DimensionPolygon:
+		if math.Abs(hull.Polygon.Normal.Z) > epsilon {
+			// All points lie on a circle, any triangulation of the polygon is a Delaunay triangulation
+			return fan(points, hull.Outline), nil
+		}
+	}
+
+	return nil, &quickhull.Error{Kind: quickhull.KindDegenerateInput, Msg: fmt.Sprintf("points span only a %v", hull.Dimension)}
+}
+
+// Triangulates the convex polygon with the given boundary as fan, the triangles are counter-clockwise.
+func fan(points []r2.Point, outline []int) [][3]int {
+	var area float64
+	for i, idx := range outline {
+		area += points[idx].Cross(points[outline[(i+1)%len(outline)]])
+	}
+
+	triangles := make([][3]int, 0, len(outline)-2)
+	for i := 1; i+1 < len(outline); i++ {
+		if area > 0 {
+			triangles = append(triangles, [3]int{outline[0], outline[i], outline[i+1]})
+		} else {
+			triangles = append(triangles, [3]int{outline[0], outline[i+1], outline[i]})
+		}
+	}
+
+	return triangles
+}
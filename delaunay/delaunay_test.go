@@ -0,0 +1,134 @@
+package delaunay
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+	"github.com/markus-wa/quickhull-go/v2/hull2d"
+)
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func assertErrorKind(t *testing.T, kind quickhull.ErrorKind, err error) {
+	t.Helper()
+
+	if e, ok := err.(*quickhull.Error); !ok || e.Kind != kind {
+		t.Errorf("expected error of kind %v, got %v", kind, err)
+	}
+}
+
+// Checks that all triangles are counter-clockwise and that no point is inside the circumcircle of any triangle.
+func assertDelaunay(t *testing.T, points []r2.Point, triangles [][3]int) {
+	t.Helper()
+
+	for _, tri := range triangles {
+		a, b, c := points[tri[0]], points[tri[1]], points[tri[2]]
+		if b.Sub(a).Cross(c.Sub(a)) <= 0 {
+			t.Fatalf("triangle %v isn't counter-clockwise", tri)
+		}
+		for i, p := range points {
+			if inCircle(a, b, c, p) > 1e-9 {
+				t.Fatalf("point %d is inside the circumcircle of %v", i, tri)
+			}
+		}
+	}
+}
+
+// Positive if p is inside the circumcircle of the counter-clockwise triangle abc.
+func inCircle(a, b, c, p r2.Point) float64 {
+	a, b, c = a.Sub(p), b.Sub(p), c.Sub(p)
+	return (a.X*a.X+a.Y*a.Y)*b.Cross(c) - (b.X*b.X+b.Y*b.Y)*a.Cross(c) + (c.X*c.X+c.Y*c.Y)*a.Cross(b)
+}
+
+func triangulatedArea(points []r2.Point, triangles [][3]int) float64 {
+	var area float64
+	for _, tri := range triangles {
+		area += points[tri[1]].Sub(points[tri[0]]).Cross(points[tri[2]].Sub(points[tri[0]])) / 2
+	}
+	return area
+}
+
+func TestTriangulateSquare(t *testing.T) {
+	points := []r2.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 5, Y: 5}}
+
+	triangles, err := Triangulate(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 4, len(triangles))
+	assertDelaunay(t, points, triangles)
+	for _, tri := range triangles {
+		if tri[0] != 4 && tri[1] != 4 && tri[2] != 4 {
+			t.Errorf("triangle %v doesn't contain the center", tri)
+		}
+	}
+}
+
+func TestTriangulateRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := make([]r2.Point, 500)
+	for i := range points {
+		points[i] = r2.Point{X: 1000 + r.Float64(), Y: 2000 + r.Float64()}
+	}
+
+	triangles, err := Triangulate(points, 0)
+	assertEqual(t, nil, err)
+	assertDelaunay(t, points, triangles)
+
+	hull, err := hull2d.ConvexHull(points, 0)
+	assertEqual(t, nil, err)
+	// Euler's formula for triangulations of points in general position
+	assertEqual(t, 2*len(points)-2-len(hull.Indices), len(triangles))
+}
+
+func TestTriangulateGrid(t *testing.T) {
+	var points []r2.Point
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			points = append(points, r2.Point{X: float64(x), Y: float64(y)})
+		}
+	}
+
+	triangles, err := Triangulate(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 2*9*9, len(triangles))
+	assertEqual(t, 81.0, triangulatedArea(points, triangles))
+	assertDelaunay(t, points, triangles)
+}
+
+func TestTriangulateCocircular(t *testing.T) {
+	var points []r2.Point
+	for i := 0; i < 8; i++ {
+		a := float64(i) * math.Pi / 4
+		points = append(points, r2.Point{X: math.Cos(a), Y: math.Sin(a)})
+	}
+
+	triangles, err := Triangulate(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 6, len(triangles))
+	assertDelaunay(t, points, triangles)
+}
+
+func TestTriangulateDegenerate(t *testing.T) {
+	_, err := Triangulate(nil, 0)
+	assertErrorKind(t, quickhull.KindDegenerateInput, err)
+
+	_, err = Triangulate([]r2.Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}, 0)
+	assertErrorKind(t, quickhull.KindDegenerateInput, err)
+
+	_, err = Triangulate([]r2.Point{{X: 0, Y: 0}, {X: math.NaN(), Y: 1}}, 0)
+	assertErrorKind(t, quickhull.KindNonFiniteInput, err)
+
+	triangles, err := Triangulate([]r2.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 1}}, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 1, len(triangles))
+}
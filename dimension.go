@@ -0,0 +1,159 @@
+package quickhull
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// Dimension is the dimension of the affine subspace spanned by a point cloud (within epsilon).
+type Dimension int
+
+const (
+	// DimensionPoint means all points coincide.
+	DimensionPoint Dimension = iota
+	// DimensionSegment means all points lie on a line, the hull is a line segment.
+	DimensionSegment
+	// DimensionPolygon means all points lie on a plane, the hull is a convex polygon.
+	DimensionPolygon
+	// DimensionPolytope means the hull is a proper three dimensional convex polytope.
+	DimensionPolytope
+)
+
+func (d Dimension) String() string {
+	switch d {
+	case DimensionPoint:
+		return "point"
+	case DimensionSegment:
+		return "segment"
+	case DimensionPolygon:
+		return "polygon"
+	case DimensionPolytope:
+		return "polytope"
+	}
+	return fmt.Sprintf("Dimension(%d)", int(d))
+}
+
+// Classification describes the subspace spanned by a point cloud.
+type Classification struct {
+	Dimension Dimension
+	Indices   []int // Indices of Dimension+1 points spanning the subspace, e.g. the endpoints of a segment
+}
+
+// Classify determines whether the point cloud is a single point, lies on a line, lies on a plane or spans a volume.
+// Epsilon is interpreted like in ConvexHull: relative to the scale of the point cloud, if <= 0 a default value will be used.
+// The result is the same Dimension that ConvexHull reports for the point cloud.
+func Classify(pointCloud []r3.Vector, epsilon float64) (Classification, error) {
+	var qh QuickHull
+	err := qh.setup(context.Background(), pointCloud, Options{Epsilon: epsilon})
+	if err != nil {
+		return Classification{}, err
+	}
+
+	dim, spanning := qh.spanningPoints()
+	return Classification{
+		Dimension: dim,
+		Indices:   append([]int(nil), spanning[:dim+1]...),
+	}, nil
+}
+
+// Finds points spanning the point cloud: the two most distant extreme points, the point most distant to the line through them
+// and the point most distant to the plane through those three. Stops as soon as no point is farther away than epsilon.
+// The first Dimension+1 returned indices are valid.
+func (qh *QuickHull) spanningPoints() (Dimension, [4]int) {
+	var spanning [4]int
+
+	// Find two most distant extreme points.
+	maxD := qh.epsilonSquared
+	var p1, p2 int
+	for i := 0; i < 6; i++ {
+		for j := i + 1; j < 6; j++ {
+			dv := qh.vertexData[qh.extremeValueIndices[i]].Sub(qh.vertexData[qh.extremeValueIndices[j]])
+			dSq := dv.X*dv.X + dv.Y*dv.Y + dv.Z*dv.Z
+			if dSq > maxD {
+				maxD = dSq
+				p1 = qh.extremeValueIndices[i]
+				p2 = qh.extremeValueIndices[j]
+			}
+		}
+	}
+
+	if maxD == qh.epsilonSquared {
+		// The point cloud seems to consist of a single point
+		return DimensionPoint, spanning
+	}
+	assertTrue(p1 != p2)
+	spanning[0], spanning[1] = p1, p2
+
+	// Find the most distant point to the line between the two chosen extreme points.
+	r := newRay(qh.vertexData[p1], qh.vertexData[p2].Sub(qh.vertexData[p1]))
+	maxD = qh.epsilonSquared
+	maxI := maxInt
+	for i, v := range qh.vertexData {
+		distToRay := squaredDistanceBetweenPointAndRay(v, r)
+		if distToRay > maxD {
+			maxD = distToRay
+			maxI = i
+		}
+	}
+
+	if maxD == qh.epsilonSquared {
+		return DimensionSegment, spanning
+	}
+	assertTrue(p1 != maxI && p2 != maxI)
+	spanning[2] = maxI
+
+	// Find the point farthest away from the plane through the three points.
+	n := triangleNormal(qh.vertexData[p1], qh.vertexData[p2], qh.vertexData[maxI])
+	trianglePlane := newPlane(n, qh.vertexData[p1])
+	maxD = qh.epsilon
+	maxI = 0
+	for i, v := range qh.vertexData {
+		d := math.Abs(signedDistanceToPlane(v, trianglePlane))
+		if d > maxD {
+			maxD = d
+			maxI = i
+		}
+	}
+
+	if maxD == qh.epsilon {
+		return DimensionPolygon, spanning
+	}
+	spanning[3] = maxI
+
+	return DimensionPolytope, spanning
+}
+
+// Returns the boundary of a planar hull as ordered loop of point indices.
+// The loop consists of the edges opposite of the given extra point, which was added above the plane to give the hull volume.
+func (qh *QuickHull) planarOutline(extraPoint int) []int {
+	next := make(map[int]int)
+	first := -1
+	for _, f := range qh.mesh.faces {
+		if f.isDisabled() {
+			continue
+		}
+		v := qh.mesh.vertexIndicesOfFace(f)
+		for i := range v {
+			if v[i] == extraPoint {
+				a, b := v[(i+1)%3], v[(i+2)%3]
+				next[a] = b
+				if first < 0 {
+					first = a
+				}
+			}
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+
+	outline := []int{first}
+	for v, ok := next[first]; ok && v != first && len(outline) < len(next); v, ok = next[v] {
+		outline = append(outline, v)
+	}
+
+	return outline
+}
@@ -0,0 +1,533 @@
+package quickhull
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// DynamicHull is a convex hull of a set of points that supports inserting and removing points.
+//
+// It is built on the same half edge mesh as QuickHull, but instead of discarding the points inside the hull it retains them:
+// each interior point is assigned to the face whose pyramid with apex at a fixed center (strictly inside the hull) contains it,
+// i.e. the face through which the ray from the center through the point leaves the hull.
+// Only the points assigned to the faces around a hull vertex can become part of the hull when that vertex is removed,
+// so removing a vertex only requires hulling its neighbors and those points, the resulting cap replaces the faces around the vertex.
+// Points inserted outside the hull are added like QuickHull adds points, points inserted inside the hull are assigned to a face
+// by walking over the mesh. Removing interior points is O(1).
+//
+// The epsilon is determined when the hull is (re)built. With a relative epsilon the hull is rebuilt if an inserted point
+// increases the scale of the point cloud, removing points doesn't decrease it.
+// Hulls that aren't three dimensional (fewer than four points or all points on a plane) are rebuilt from scratch on every change.
+// The budgets of the Options (MaxIterations, MaxFaces and Timeout), CullInterior and Joggle are ignored.
+type DynamicHull struct {
+	qh       QuickHull
+	opts     Options
+	interior retainedPoints
+
+	points   []r3.Vector // Indexed by point ID
+	alive    []bool
+	scale    float64
+	lo, hi   r3.Vector // Bounding box of the points at the last (re)build
+	proper   bool      // Whether the mesh is a three dimensional hull and the interior points are assigned to its faces
+	lastFace int       // Face on which the walk to the face of an inserted point starts
+}
+
+// Interior points of a hull, see DynamicHull.
+type retainedPoints struct {
+	center  r3.Vector // Apex of the pyramids of the Faces, strictly inside the hull
+	owner   []int     // Face whose pyramid contains an interior point, -1 for vertices and points outside of the hull
+	pos     []int     // Position of an interior point in the interiorPoints of its Face
+	edge    []int     // Half edge ending at a vertex, -1 for points that aren't vertices
+	pending []int     // Points that lost their Face on the current iteration and need to be assigned to one of the new Faces
+}
+
+// NewDynamicHull creates a DynamicHull of the given point cloud.
+// Point IDs are indices into pointCloud, points inserted later get consecutive IDs.
+// The point cloud is copied.
+func NewDynamicHull(pointCloud []r3.Vector, opts Options) (*DynamicHull, error) {
+	for i, v := range pointCloud {
+		if !isFinite(v) {
+			return nil, newError(KindNonFiniteInput, "point %d is %v", i, v)
+		}
+	}
+
+	opts.MaxIterations, opts.MaxFaces, opts.Timeout = 0, 0, 0
+	opts.CullInterior = false // Culled points couldn't become part of the hull when vertices are removed
+	dh := &DynamicHull{
+		opts:   opts,
+		points: append([]r3.Vector(nil), pointCloud...),
+	}
+	dh.alive = make([]bool, len(dh.points))
+	for i := range dh.alive {
+		dh.alive[i] = true
+	}
+
+	err := dh.rebuild()
+	if err != nil && !isPartialResult(err) {
+		return nil, err
+	}
+
+	return dh, err
+}
+
+// Insert adds a point to the hull and returns its ID.
+func (dh *DynamicHull) Insert(p r3.Vector) (id int, err error) {
+	defer func() {
+		if e := errorFromPanic(recover()); e != nil {
+			err = e
+		}
+	}()
+
+	if !isFinite(p) {
+		return -1, newError(KindNonFiniteInput, "point is %v", p)
+	}
+
+	id = len(dh.points)
+	dh.points = append(dh.points, p)
+	dh.alive = append(dh.alive, true)
+	dh.interior.owner = append(dh.interior.owner, -1)
+	dh.interior.pos = append(dh.interior.pos, 0)
+	dh.interior.edge = append(dh.interior.edge, -1)
+
+	// With a relative epsilon a larger scale changes the epsilon, which in turn may change the hull.
+	// Normalized points are scaled by the bounding box instead, so any change of it counts.
+	scaleGrew := false
+	if dh.opts.EpsilonMode == RelativeEpsilon {
+		if dh.opts.Normalize {
+			scaleGrew = p.X < dh.lo.X || p.Y < dh.lo.Y || p.Z < dh.lo.Z || p.X > dh.hi.X || p.Y > dh.hi.Y || p.Z > dh.hi.Z
+		} else {
+			scaleGrew = scale(dh.points[id:], extremeValues(dh.points[id:])) > dh.scale
+		}
+	}
+	if !dh.proper || scaleGrew {
+		return id, dh.rebuild()
+	}
+
+	dh.qh.vertexData = dh.points
+
+	f := dh.locate(id)
+	if _, outside := dh.qh.distanceIfOnPositiveSide(&dh.qh.mesh.faces[f], id); outside {
+		// The point leaves the hull through the face, so it's going to be a vertex
+		return id, dh.qh.extendMesh(context.Background(), id, dh.opts)
+	}
+
+	dh.qh.addInteriorPoint(f, id)
+
+	return id, nil
+}
+
+// Remove removes the point with the given ID from the hull.
+func (dh *DynamicHull) Remove(id int) (err error) {
+	defer func() {
+		if e := errorFromPanic(recover()); e != nil {
+			err = e
+		}
+	}()
+
+	if id < 0 || id >= len(dh.points) || !dh.alive[id] {
+		return newError(KindInvalidArgument, "point %d doesn't exist", id)
+	}
+
+	dh.alive[id] = false
+
+	switch {
+	case !dh.proper:
+		return dh.rebuild()
+	case dh.interior.owner[id] >= 0:
+		dh.qh.removeInteriorPoint(id)
+		return nil
+	case dh.interior.edge[id] < 0:
+		// Neither a vertex nor inside the hull: the point was dropped because its horizon edge couldn't be solved
+		return nil
+	}
+
+	return dh.removeVertex(id)
+}
+
+// Contains reports whether the point with the given ID exists (i.e. it was part of the initial point cloud or inserted, and wasn't removed).
+func (dh *DynamicHull) Contains(id int) bool {
+	return id >= 0 && id < len(dh.points) && dh.alive[id]
+}
+
+// Points returns all points ever added to the hull, indexed by ID. Removed points are still included.
+// The returned slice must not be modified.
+func (dh *DynamicHull) Points() []r3.Vector {
+	return dh.points
+}
+
+// ConvexHull returns the current hull as ConvexHull.
+// With OriginalIndices the vertices are Points() and indices are point IDs.
+func (dh *DynamicHull) ConvexHull() ConvexHull {
+	hull := newConvexHull(dh.qh.mesh, dh.points, dh.opts, dh.qh.dimension, dh.qh.outline)
+	hull.Epsilon = dh.qh.epsilon * dh.qh.unit
+	return hull
+}
+
+// Rebuilds the hull and all assignments from all alive points.
+func (dh *DynamicHull) rebuild() error {
+	var ids []int
+	for i, alive := range dh.alive {
+		if alive {
+			ids = append(ids, i)
+		}
+	}
+
+	dh.interior.reset(len(ids))
+	dh.qh.retained = &dh.interior
+	dh.qh.mesh = meshBuilder{}
+	dh.qh.dimension = DimensionPoint
+	dh.qh.outline = nil
+	dh.qh.vertexData = dh.points
+	dh.proper = false
+	dh.lastFace = 0
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pointCloud := make([]r3.Vector, len(ids))
+	for i, id := range ids {
+		pointCloud[i] = dh.points[id]
+	}
+	extremes := extremeValues(pointCloud)
+	dh.scale = scale(pointCloud, extremes)
+	dh.lo, dh.hi = boundingBox(pointCloud, extremes)
+
+	err := dh.qh.buildMesh(context.Background(), pointCloud, dh.opts)
+	if err != nil && !isPartialResult(err) {
+		return err
+	}
+
+	// Point indices of the mesh refer to the alive points, turn them into IDs
+	dh.qh.mesh.mapPointIndices(ids)
+	dh.interior.mapPointIndices(ids, len(dh.points))
+	for i, idx := range dh.qh.outline {
+		dh.qh.outline[i] = ids[idx]
+	}
+	dh.qh.vertexData = dh.points
+	dh.proper = dh.qh.dimension == DimensionPolytope
+
+	if dh.opts.Normalize && dh.proper {
+		// The mesh was built from normalized points, but the points inserted and removed later aren't normalized
+		center, unit := normalization(dh.lo, dh.hi)
+		dh.qh.epsilon *= unit
+		dh.qh.epsilonSquared = dh.qh.epsilon * dh.qh.epsilon
+		dh.qh.unit = 1
+		dh.interior.center = dh.interior.center.Mul(unit).Add(center)
+		dh.qh.recomputePlanes()
+	}
+
+	return err
+}
+
+// Returns the face whose pyramid contains the point by walking over the mesh towards it.
+func (dh *DynamicHull) locate(id int) int {
+	mesh := &dh.qh.mesh
+
+	f := dh.lastFace
+	if f >= len(mesh.faces) || mesh.faces[f].isDisabled() {
+		f = 0
+		for mesh.faces[f].isDisabled() {
+			f++
+		}
+	}
+
+	for steps := len(mesh.faces); steps > 0; steps-- {
+		s := dh.qh.pyramidCoordinates(mesh.faces[f], id)
+		j := 0
+		for k := 1; k < 3; k++ {
+			if s[k] < s[j] {
+				j = k
+			}
+		}
+		if s[j] >= 0 {
+			dh.lastFace = f
+			return f
+		}
+		// The point is beyond the j-th edge, continue with the face on the other side
+		he := mesh.halfEdgeIndicesOfFace(mesh.faces[f])[j]
+		f = mesh.halfEdges[mesh.halfEdges[he].Opp].Face
+	}
+
+	// Due to rounding errors the walk may run in circles, check all faces instead
+	var faces []int
+	for i := range mesh.faces {
+		if !mesh.faces[i].isDisabled() {
+			faces = append(faces, i)
+		}
+	}
+	return dh.qh.pyramidOf(id, faces)
+}
+
+// Removes a hull vertex by replacing the faces around it with the cap of the hull of its neighbors and the points assigned to those faces.
+// The neighbors form the link of the vertex, which is the boundary of the cap. Falls back to rebuilding the hull if the cap
+// doesn't fit into the link (e.g. due to coplanar points) or the center isn't strictly inside the new hull.
+func (dh *DynamicHull) removeVertex(v int) error {
+	mesh := &dh.qh.mesh
+	r := &dh.interior
+
+	// Walk around the vertex: every half edge ending at it is followed by one starting at it and then by an edge of the link
+	var star, link []int
+	for he := r.edge[v]; ; {
+		next := mesh.halfEdges[he].Next
+		star = append(star, mesh.halfEdges[he].Face)
+		link = append(link, mesh.halfEdges[next].Next)
+		he = mesh.halfEdges[next].Opp
+		if he == r.edge[v] {
+			break
+		}
+		assertTrue(len(star) < len(mesh.faces))
+	}
+
+	// The candidates are the link vertices and the points assigned to the faces around the vertex, followed by the opposite
+	// vertices of the faces on the other side of the link. Those aren't part of the cap, but make sure it's convex at the link.
+	var ids []int
+	local := make(map[int]int)
+	add := func(id int) {
+		if _, found := local[id]; !found {
+			local[id] = len(ids)
+			ids = append(ids, id)
+		}
+	}
+	for _, he := range link {
+		add(mesh.halfEdges[he].EndVertex)
+	}
+	for _, f := range star {
+		for _, id := range mesh.faces[f].interiorPoints {
+			add(id)
+		}
+	}
+	nCandidates := len(ids)
+	outer := make(map[[2]int]int, len(link)) // Maps start and end vertex of each link edge to the half edge on the other side
+	for _, he := range link {
+		opp := mesh.halfEdges[he].Opp
+		outer[[2]int{mesh.halfEdges[opp].EndVertex, mesh.halfEdges[he].EndVertex}] = opp
+		add(mesh.halfEdges[mesh.halfEdges[opp].Next].EndVertex)
+	}
+
+	pointCloud := make([]r3.Vector, len(ids))
+	for i, id := range ids {
+		pointCloud[i] = dh.points[id]
+	}
+
+	var capHull QuickHull
+	err := capHull.buildMesh(context.Background(), pointCloud, Options{Epsilon: dh.qh.epsilon, EpsilonMode: AbsoluteEpsilon, Robust: dh.opts.Robust})
+	if err != nil || capHull.dimension < DimensionPolytope {
+		return dh.rebuild()
+	}
+
+	// The cap consists of the faces visible from the removed vertex, it must be bounded by the link
+	visible := make([]bool, len(capHull.mesh.faces))
+	var capFaces []int
+	for i := range capHull.mesh.faces {
+		f := &capHull.mesh.faces[i]
+		if f.isDisabled() || signedDistanceToPlane(dh.points[v], f.plane) <= 0 {
+			continue
+		}
+		d := signedDistanceToPlane(r.center, f.plane)
+		if d >= 0 || d*d <= dh.qh.epsilonSquared*f.plane.sqrNLength {
+			return dh.rebuild()
+		}
+		visible[i] = true
+		capFaces = append(capFaces, i)
+	}
+	matched := make(map[[2]int]bool, len(link))
+	for _, f := range capFaces {
+		for _, he := range capHull.mesh.halfEdgeIndicesOfFace(capHull.mesh.faces[f]) {
+			if capHull.mesh.halfEdges[he].EndVertex >= nCandidates {
+				return dh.rebuild()
+			}
+			opp := capHull.mesh.halfEdges[he].Opp
+			if visible[capHull.mesh.halfEdges[opp].Face] {
+				continue
+			}
+			e := dh.capEdge(&capHull, ids, he)
+			if _, found := outer[e]; !found || matched[e] {
+				return dh.rebuild()
+			}
+			matched[e] = true
+		}
+	}
+	if len(matched) != len(link) {
+		return dh.rebuild()
+	}
+
+	// Replace the faces around the vertex by the cap
+	for _, f := range star {
+		for _, id := range mesh.faces[f].interiorPoints {
+			r.owner[id] = -1
+			r.pending = append(r.pending, id)
+		}
+		mesh.faces[f].interiorPoints = nil
+		for _, he := range mesh.halfEdgeIndicesOfFace(mesh.faces[f]) {
+			mesh.disableHalfEdge(he)
+		}
+		mesh.disableFace(f)
+	}
+	r.edge[v] = -1
+
+	halfEdges := make(map[int]int, 3*len(capFaces)) // Maps half edges of the cap hull to half edges of the mesh
+	newFaces := make([]int, len(capFaces))
+	for i, cf := range capFaces {
+		f := mesh.addFace()
+		newFaces[i] = f
+
+		capHalfEdges := capHull.mesh.halfEdgeIndicesOfFace(capHull.mesh.faces[cf])
+		var hes [3]int
+		for j, he := range capHalfEdges {
+			hes[j] = mesh.addHalfEdge()
+			halfEdges[he] = hes[j]
+		}
+		for j, he := range hes {
+			mesh.halfEdges[he] = HalfEdge{EndVertex: ids[capHull.mesh.halfEdges[capHalfEdges[j]].EndVertex], Face: f, Next: hes[(j+1)%3]}
+		}
+		mesh.faces[f] = meshBuilderFace{halfEdgeIndex: hes[0], plane: capHull.mesh.faces[cf].plane}
+	}
+	for capHe, he := range halfEdges {
+		if opp, found := halfEdges[capHull.mesh.halfEdges[capHe].Opp]; found {
+			mesh.halfEdges[he].Opp = opp
+			continue
+		}
+		opp := outer[dh.capEdge(&capHull, ids, capHe)]
+		mesh.halfEdges[he].Opp = opp
+		mesh.halfEdges[opp].Opp = he
+	}
+
+	dh.qh.retainInteriorPoints(newFaces)
+	dh.lastFace = newFaces[0]
+
+	return nil
+}
+
+// Returns the start and end vertex (IDs) of a half edge of the cap hull.
+func (dh *DynamicHull) capEdge(capHull *QuickHull, ids []int, he int) [2]int {
+	opp := capHull.mesh.halfEdges[he].Opp
+	return [2]int{ids[capHull.mesh.halfEdges[opp].EndVertex], ids[capHull.mesh.halfEdges[he].EndVertex]}
+}
+
+func (r *retainedPoints) reset(n int) {
+	r.owner = make([]int, n)
+	r.pos = make([]int, n)
+	r.edge = make([]int, n)
+	for i := 0; i < n; i++ {
+		r.owner[i] = -1
+		r.edge[i] = -1
+	}
+	r.pending = r.pending[:0]
+}
+
+// Replaces all point indices i by mapping[i], n is the number of points after the mapping.
+func (r *retainedPoints) mapPointIndices(mapping []int, n int) {
+	owner, pos, edge := r.owner, r.pos, r.edge
+	r.reset(n)
+	for i, j := range mapping {
+		r.owner[j] = owner[i]
+		r.pos[j] = pos[i]
+		r.edge[j] = edge[i]
+	}
+}
+
+// Reports whether interior points are kept track of, which is only done for three dimensional hulls.
+func (qh *QuickHull) retaining() bool {
+	return qh.retained != nil && qh.dimension == DimensionPolytope
+}
+
+// Assigns the points inside the initial tetrahedron to its Faces, using the centroid of the tetrahedron as center.
+func (qh *QuickHull) retainInitialPoints() {
+	var center r3.Vector
+	for _, v := range qh.mesh.vertexIndicesOfFace(qh.mesh.faces[0]) {
+		center = center.Add(qh.vertexData[v])
+	}
+	// The vertex opposite of the first Face is the end of the second half edge of the second Face
+	center = center.Add(qh.vertexData[qh.mesh.vertexIndicesOfFace(qh.mesh.faces[1])[1]])
+	qh.retained.center = center.Mul(0.25)
+
+	qh.retainInteriorPoints([]int{0, 1, 2, 3})
+}
+
+// Takes the interior points of the visible Faces, which are about to be replaced, along with the vertices of those Faces.
+func (qh *QuickHull) releaseInteriorPoints(visibleFaces []int) {
+	r := qh.retained
+	for _, fi := range visibleFaces {
+		f := &qh.mesh.faces[fi]
+		for _, p := range f.interiorPoints {
+			r.owner[p] = -1
+			r.pending = append(r.pending, p)
+		}
+		f.interiorPoints = nil
+		for _, v := range qh.mesh.vertexIndicesOfFace(*f) {
+			if r.edge[v] >= 0 {
+				// Vertices on the horizon get a new half edge when the new Faces are registered
+				r.edge[v] = -1
+				r.pending = append(r.pending, v)
+			}
+		}
+	}
+}
+
+// Registers the vertices of the new Faces and assigns the pending points which didn't turn out to be vertices to them.
+func (qh *QuickHull) retainInteriorPoints(newFaces []int) {
+	r := qh.retained
+	for _, fi := range newFaces {
+		for _, he := range qh.mesh.halfEdgeIndicesOfFace(qh.mesh.faces[fi]) {
+			r.edge[qh.mesh.halfEdges[he].EndVertex] = he
+		}
+	}
+
+	for _, p := range r.pending {
+		if r.edge[p] < 0 && r.owner[p] < 0 {
+			qh.addInteriorPoint(qh.pyramidOf(p, newFaces), p)
+		}
+	}
+	r.pending = r.pending[:0]
+}
+
+func (qh *QuickHull) addInteriorPoint(faceIndex int, pointIndex int) {
+	f := &qh.mesh.faces[faceIndex]
+	qh.retained.owner[pointIndex] = faceIndex
+	qh.retained.pos[pointIndex] = len(f.interiorPoints)
+	f.interiorPoints = append(f.interiorPoints, pointIndex)
+}
+
+func (qh *QuickHull) removeInteriorPoint(pointIndex int) {
+	r := qh.retained
+	f := &qh.mesh.faces[r.owner[pointIndex]]
+	pos := r.pos[pointIndex]
+	last := f.interiorPoints[len(f.interiorPoints)-1]
+	f.interiorPoints[pos] = last
+	r.pos[last] = pos
+	f.interiorPoints = f.interiorPoints[:len(f.interiorPoints)-1]
+	r.owner[pointIndex] = -1
+}
+
+// Returns the one of the given Faces whose pyramid contains the point.
+// Points that are on the boundary of the pyramids (or outside all of them due to rounding errors) go to the Face they are deepest inside of.
+func (qh *QuickHull) pyramidOf(pointIndex int, faces []int) int {
+	best, bestS := -1, math.Inf(-1)
+	for _, fi := range faces {
+		s := qh.pyramidCoordinates(qh.mesh.faces[fi], pointIndex)
+		if m := math.Min(s[0], math.Min(s[1], s[2])); m > bestS {
+			best, bestS = fi, m
+		}
+	}
+	return best
+}
+
+// Returns for each half edge of the Face how far the point is inside of the plane through the center and the half edge,
+// relative to the opposite vertex. The point is in the pyramid of the Face if all of them are >= 0.
+func (qh *QuickHull) pyramidCoordinates(f meshBuilderFace, pointIndex int) [3]float64 {
+	c := qh.retained.center
+	d := qh.vertexData[pointIndex].Sub(c)
+	v := qh.mesh.vertexIndicesOfFace(f)
+
+	var s [3]float64
+	for j := range s {
+		// The j-th half edge goes from vertex j-1 to vertex j
+		a, b, opposite := qh.vertexData[v[(j+2)%3]].Sub(c), qh.vertexData[v[j]].Sub(c), qh.vertexData[v[(j+1)%3]].Sub(c)
+		n := a.Cross(b)
+		s[j] = n.Dot(d) / n.Dot(opposite)
+	}
+	return s
+}
@@ -0,0 +1,227 @@
+package quickhull
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestDynamicHull(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pointCloud := randomPointCloud(500)
+
+	dh, err := NewDynamicHull(pointCloud, Options{})
+	assertEqual(t, nil, err)
+
+	ids := make([]int, len(pointCloud))
+	for i := range ids {
+		ids[i] = i
+	}
+
+	for step := 0; step < 300; step++ {
+		if step%4 == 3 {
+			// Insert a point, sometimes outside the current hull
+			p := r3.Vector{X: randF64(-1.2, 1.2), Y: randF64(-1.2, 1.2), Z: randF64(-1.2, 1.2)}
+			id, err := dh.Insert(p)
+			assertEqual(t, nil, err)
+			ids = append(ids, id)
+		} else {
+			// Prefer removing hull vertices, those are the interesting case
+			var id int
+			hull := dh.ConvexHull()
+			if step%2 == 0 {
+				id = findID(dh, hull.Vertices[r.Intn(len(hull.Vertices))])
+			} else {
+				id = ids[r.Intn(len(ids))]
+			}
+			assertEqual(t, nil, dh.Remove(id))
+			ids = removeID(ids, id)
+		}
+
+		remaining := make([]r3.Vector, len(ids))
+		for i, id := range ids {
+			remaining[i] = dh.Points()[id]
+		}
+
+		expected := convexHull(remaining)
+		actual := dh.ConvexHull()
+		assertElementsMatch(t, expected.Vertices, actual.Vertices, "dynamic hull should match hull of remaining points after step ", step)
+		assertClosedMesh(t, actual)
+		assertInteriorPointsAssigned(t, dh, ids)
+	}
+}
+
+func TestDynamicHullNormalize(t *testing.T) {
+	// Far away from the origin the relative epsilon would be too large without normalization
+	r := rand.New(rand.NewSource(2))
+	offset := r3.Vector{X: 1e6, Y: -1e6, Z: 1e6}
+	var pointCloud []r3.Vector
+	for _, p := range randomPointCloud(300) {
+		pointCloud = append(pointCloud, p.Add(offset))
+	}
+	opts := Options{Normalize: true}
+
+	dh, err := NewDynamicHull(pointCloud, opts)
+	assertEqual(t, nil, err)
+
+	ids := make([]int, len(pointCloud))
+	for i := range ids {
+		ids[i] = i
+	}
+
+	for step := 0; step < 60; step++ {
+		if step%3 == 2 {
+			// Inside the bounding box, so the hull isn't rebuilt
+			id, err := dh.Insert(r3.Vector{X: randF64(-0.9, 0.9), Y: randF64(-0.9, 0.9), Z: randF64(-0.9, 0.9)}.Add(offset))
+			assertEqual(t, nil, err)
+			ids = append(ids, id)
+		} else {
+			hull := dh.ConvexHull()
+			id := findID(dh, hull.Vertices[r.Intn(len(hull.Vertices))])
+			assertEqual(t, nil, dh.Remove(id))
+			ids = removeID(ids, id)
+		}
+
+		remaining := make([]r3.Vector, len(ids))
+		for i, id := range ids {
+			remaining[i] = dh.Points()[id]
+		}
+
+		expected, err := new(QuickHull).TryConvexHull(remaining, opts)
+		assertEqual(t, nil, err)
+		actual := dh.ConvexHull()
+		assertElementsMatch(t, expected.Vertices, actual.Vertices, "dynamic hull should match hull of remaining points after step ", step)
+		assertClosedMesh(t, actual)
+	}
+}
+
+func TestDynamicHullLattice(t *testing.T) {
+	// Lots of coplanar points, the faces around removed vertices often can't be replaced locally
+	r := rand.New(rand.NewSource(1))
+	var pointCloud []r3.Vector
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			for z := 0; z < 5; z++ {
+				pointCloud = append(pointCloud, r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)})
+			}
+		}
+	}
+
+	dh, err := NewDynamicHull(pointCloud, Options{})
+	assertEqual(t, nil, err)
+
+	ids := make([]int, len(pointCloud))
+	for i := range ids {
+		ids[i] = i
+	}
+
+	for step := 0; step < 40; step++ {
+		hull := dh.ConvexHull()
+		id := findID(dh, hull.Vertices[r.Intn(len(hull.Vertices))])
+		assertEqual(t, nil, dh.Remove(id))
+		ids = removeID(ids, id)
+
+		hull = dh.ConvexHull()
+		assertClosedMesh(t, hull)
+		assertInteriorPointsAssigned(t, dh, ids)
+
+		// All remaining points must be inside the hull and all vertices must be remaining points
+		for _, tri := range hull.Triangles() {
+			n := triangleNormal(tri[0], tri[2], tri[1]).Normalize()
+			for _, id := range ids {
+				if d := n.Dot(dh.Points()[id].Sub(tri[0])); d > 1e-9 {
+					t.Errorf("point %d is outside of triangle %v after step %d", id, tri, step)
+				}
+			}
+			for _, v := range tri {
+				if findID(dh, v) < 0 {
+					t.Errorf("vertex %v was removed before step %d", v, step)
+				}
+			}
+		}
+	}
+}
+
+func TestDynamicHullDegenerate(t *testing.T) {
+	dh, err := NewDynamicHull([]r3.Vector{{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}}, Options{})
+	assertEqual(t, nil, err)
+	assertEqual(t, DimensionSegment, dh.ConvexHull().Dimension)
+	assertEqual(t, 2, len(dh.ConvexHull().Vertices))
+
+	for _, p := range []r3.Vector{{X: 0, Y: 1, Z: 0}, {X: 0, Y: 0, Z: 1}, {X: 0.1, Y: 0.1, Z: 0.1}} {
+		_, err = dh.Insert(p)
+		assertEqual(t, nil, err)
+	}
+	assertEqual(t, 4, len(dh.ConvexHull().Vertices))
+
+	assertEqual(t, nil, dh.Remove(3))
+	assertEqual(t, false, dh.Contains(3))
+	assertElementsMatch(t, []r3.Vector{{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}, {X: 0, Y: 1, Z: 0}, {X: 0.1, Y: 0.1, Z: 0.1}}, dh.ConvexHull().Vertices)
+
+	assertErrorKind(t, KindInvalidArgument, dh.Remove(3))
+}
+
+// Every remaining point must either be a vertex or be assigned to a face of the hull.
+func assertInteriorPointsAssigned(t *testing.T, dh *DynamicHull, ids []int) {
+	t.Helper()
+
+	if !dh.proper {
+		return
+	}
+
+	for _, id := range ids {
+		owner := dh.interior.owner[id]
+		switch {
+		case owner >= 0:
+			points := dh.qh.mesh.faces[owner].interiorPoints
+			if dh.qh.mesh.faces[owner].isDisabled() || points[dh.interior.pos[id]] != id {
+				t.Errorf("point %d isn't in the interior points of face %d", id, owner)
+			}
+		case dh.interior.edge[id] < 0:
+			t.Errorf("point %d is neither a vertex nor assigned to a face", id)
+		}
+	}
+}
+
+func findID(dh *DynamicHull, p r3.Vector) int {
+	for i, q := range dh.Points() {
+		if q == p && dh.Contains(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeID(ids []int, id int) []int {
+	for i := range ids {
+		if ids[i] == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+func BenchmarkDynamicHullRemove(b *testing.B) {
+	pointCloud := randomPointCloud(10000)
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		dh, _ := NewDynamicHull(pointCloud, Options{})
+		b.StartTimer()
+
+		for id := 0; id < 1000; id++ {
+			_ = dh.Remove(id)
+		}
+	}
+}
+
+func BenchmarkDynamicHullRebuild(b *testing.B) {
+	pointCloud := randomPointCloud(10000)
+
+	for i := 0; i < b.N; i++ {
+		for id := 0; id < 1000; id++ {
+			new(QuickHull).ConvexHull(pointCloud[id+1:], true, false, 0)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package quickhull
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// ErrorKind classifies the errors returned by QuickHull.
+type ErrorKind int
+
+const (
+	// KindInternal indicates that an internal invariant of the algorithm was violated.
+	KindInternal ErrorKind = iota
+	// KindDegenerateInput indicates that the point cloud contains no points.
+	KindDegenerateInput
+	// KindNonFiniteInput indicates that the point cloud contains NaN or infinite coordinates.
+	KindNonFiniteInput
+	// KindHorizonFailure indicates that the horizon edge could not be solved for at least one point.
+	KindHorizonFailure
+	// KindIterationLimit indicates that the construction was stopped because Options.MaxIterations was reached.
+	KindIterationLimit
+	// KindFaceLimit indicates that the construction was stopped because Options.MaxFaces was reached.
+	KindFaceLimit
+	// KindInvalidArgument indicates that an argument was invalid, e.g. the ID of a point that doesn't exist.
+	KindInvalidArgument
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindInternal:
+		return "internal invariant violated"
+	case KindDegenerateInput:
+		return "degenerate input"
+	case KindNonFiniteInput:
+		return "non-finite input"
+	case KindHorizonFailure:
+		return "failed to solve horizon edge"
+	case KindIterationLimit:
+		return "iteration limit reached"
+	case KindFaceLimit:
+		return "face limit reached"
+	case KindInvalidArgument:
+		return "invalid argument"
+	}
+	return fmt.Sprintf("ErrorKind(%d)", int(k))
+}
+
+// Error is the error type returned by QuickHull.
+// Use the Kind field (or errors.Is with one of the Err* values) to find out what went wrong.
+type Error struct {
+	Kind ErrorKind
+	Msg  string // Optional details, may be empty
+}
+
+func (e *Error) Error() string {
+	if e.Msg == "" {
+		return "quickhull: " + e.Kind.String()
+	}
+	return "quickhull: " + e.Kind.String() + ": " + e.Msg
+}
+
+// Is reports whether target is an *Error of the same kind without details (i.e. one of the Err* values).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Msg == "" && t.Kind == e.Kind
+}
+
+// Errors that may be returned by QuickHull. Returned errors may carry additional details, use errors.Is or compare Error.Kind.
+var (
+	ErrInternal        = &Error{Kind: KindInternal}
+	ErrDegenerateInput = &Error{Kind: KindDegenerateInput}
+	ErrNonFiniteInput  = &Error{Kind: KindNonFiniteInput}
+	ErrHorizonFailure  = &Error{Kind: KindHorizonFailure}
+	ErrIterationLimit  = &Error{Kind: KindIterationLimit}
+	ErrFaceLimit       = &Error{Kind: KindFaceLimit}
+	ErrInvalidArgument = &Error{Kind: KindInvalidArgument}
+)
+
+func newError(kind ErrorKind, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isErrorKind(err error, kind ErrorKind) bool {
+	e, ok := err.(*Error)
+	return ok && e.Kind == kind
+}
+
+// Converts a recovered panic caused by a violated invariant into an error. Unrelated panics are re-raised.
+func errorFromPanic(r interface{}) error {
+	switch e := r.(type) {
+	case nil:
+		return nil
+	case *Error:
+		return e
+	case runtime.Error:
+		return newError(KindInternal, "%v", e)
+	}
+	panic(r)
+}
@@ -0,0 +1,245 @@
+package quickhull
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// SupportMapping is a convex shape given by its support function. ConvexHull and HalfEdgeMesh implement it.
+type SupportMapping interface {
+	// SupportPoint returns a point of the shape that is farthest in the given direction.
+	SupportPoint(direction r3.Vector) r3.Vector
+}
+
+// Transform is a rigid transformation: a rotation followed by a translation.
+type Transform struct {
+	Rotation    [3][3]float64 // Orthonormal rotation matrix, rows are X, Y and Z
+	Translation r3.Vector
+}
+
+// IdentityTransform returns the transform that doesn't move anything.
+func IdentityTransform() Transform {
+	return Transform{Rotation: [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}}
+}
+
+// Apply returns the transformed point.
+func (t Transform) Apply(p r3.Vector) r3.Vector {
+	return t.rotate(p).Add(t.Translation)
+}
+
+func (t Transform) rotate(v r3.Vector) r3.Vector {
+	r := t.Rotation
+	return r3.Vector{
+		X: r[0][0]*v.X + r[0][1]*v.Y + r[0][2]*v.Z,
+		Y: r[1][0]*v.X + r[1][1]*v.Y + r[1][2]*v.Z,
+		Z: r[2][0]*v.X + r[2][1]*v.Y + r[2][2]*v.Z,
+	}
+}
+
+// Rotates by the inverse (transposed) rotation.
+func (t Transform) rotateInverse(v r3.Vector) r3.Vector {
+	r := t.Rotation
+	return r3.Vector{
+		X: r[0][0]*v.X + r[1][0]*v.Y + r[2][0]*v.Z,
+		Y: r[0][1]*v.X + r[1][1]*v.Y + r[2][1]*v.Z,
+		Z: r[0][2]*v.X + r[1][2]*v.Y + r[2][2]*v.Z,
+	}
+}
+
+// Separation is the result of a distance query between two convex shapes.
+type Separation struct {
+	Distance       float64   // Minimum distance between the shapes, 0 if they overlap
+	PointA, PointB r3.Vector // Closest points of the shapes (after applying the transforms), only meaningful if they don't overlap
+	Overlap        bool      // Whether the shapes intersect (or touch)
+}
+
+const (
+	gjkMaxIterations = 128
+	gjkTolerance     = 1e-12 // Relative tolerance for the convergence of the distance
+)
+
+// Distance computes the minimum distance and the closest points between two convex shapes using the GJK algorithm.
+// The transforms are applied to the shapes before the query, nil means the identity.
+func Distance(a, b SupportMapping, transformA, transformB *Transform) Separation {
+	g := gjk{a: a, b: b, transformA: transformA, transformB: transformB}
+	return g.run()
+}
+
+// Overlap reports whether two convex shapes intersect (or touch), see Distance.
+func Overlap(a, b SupportMapping, transformA, transformB *Transform) bool {
+	return Distance(a, b, transformA, transformB).Overlap
+}
+
+// Point of the Minkowski difference A - B along with the points of A and B it was made of.
+type simplexVertex struct {
+	w, a, b r3.Vector
+}
+
+type gjk struct {
+	a, b                   SupportMapping
+	transformA, transformB *Transform
+
+	simplex []simplexVertex
+	lambdas []float64 // Barycentric coordinates of the closest point of the simplex to the origin
+}
+
+func (g *gjk) supportA(d r3.Vector) r3.Vector {
+	if g.transformA == nil {
+		return g.a.SupportPoint(d)
+	}
+	return g.transformA.Apply(g.a.SupportPoint(g.transformA.rotateInverse(d)))
+}
+
+func (g *gjk) supportB(d r3.Vector) r3.Vector {
+	if g.transformB == nil {
+		return g.b.SupportPoint(d)
+	}
+	return g.transformB.Apply(g.b.SupportPoint(g.transformB.rotateInverse(d)))
+}
+
+// Returns the support point of A - B in direction d.
+func (g *gjk) support(d r3.Vector) simplexVertex {
+	a, b := g.supportA(d), g.supportB(d.Mul(-1))
+	return simplexVertex{w: a.Sub(b), a: a, b: b}
+}
+
+func (g *gjk) run() Separation {
+	first := g.support(r3.Vector{X: 1})
+	g.simplex = []simplexVertex{first}
+	g.lambdas = []float64{1}
+	v := first.w
+	scale := v.Norm2()
+
+	for i := 0; i < gjkMaxIterations; i++ {
+		vv := v.Norm2()
+		if vv <= gjkTolerance*gjkTolerance*scale {
+			return Separation{Overlap: true}
+		}
+
+		w := g.support(v.Mul(-1))
+		scale = math.Max(scale, w.w.Norm2())
+		if vv-v.Dot(w.w) <= gjkTolerance*vv {
+			// w isn't closer to the origin than v in direction v, v is the closest point of A - B
+			break
+		}
+
+		g.simplex = append(g.simplex, w)
+		if !g.reduce() {
+			// The origin is inside the tetrahedron
+			return Separation{Overlap: true}
+		}
+
+		next := g.closest()
+		if next.Norm2() >= vv {
+			// No progress, due to rounding errors
+			break
+		}
+		v = next
+	}
+
+	sep := Separation{Distance: v.Norm()}
+	for i, sv := range g.simplex {
+		sep.PointA = sep.PointA.Add(sv.a.Mul(g.lambdas[i]))
+		sep.PointB = sep.PointB.Add(sv.b.Mul(g.lambdas[i]))
+	}
+	sep.Overlap = sep.Distance <= gjkTolerance*math.Sqrt(scale)
+	return sep
+}
+
+// Returns the point of the simplex closest to the origin.
+func (g *gjk) closest() r3.Vector {
+	var v r3.Vector
+	for i, sv := range g.simplex {
+		v = v.Add(sv.w.Mul(g.lambdas[i]))
+	}
+	return v
+}
+
+// Reduces the simplex to the smallest sub-simplex containing its closest point to the origin and computes the barycentric
+// coordinates of that point. Returns false if the origin is inside the simplex (which must be a tetrahedron then).
+func (g *gjk) reduce() bool {
+	var origin r3.Vector
+	s := g.simplex
+
+	switch len(s) {
+	case 2:
+		q := closestPointOnSegment(origin, s[0].w, s[1].w)
+		switch q {
+		case s[0].w:
+			g.keep(1)
+		case s[1].w:
+			g.keep(2)
+		default:
+			g.keep(1 | 2)
+		}
+
+	case 3:
+		_, corners := closestPointOnTriangle(origin, s[0].w, s[1].w, s[2].w)
+		g.keep(corners)
+
+	case 4:
+		// The closest point is on one of the faces that have the origin on their outer side
+		flat := orientation(s[0].w, s[1].w, s[2].w, s[3].w) == 0
+		bestD, best := math.Inf(1), uint(0)
+		for i := range s {
+			face := [3]int{(i + 1) % 4, (i + 2) % 4, (i + 3) % 4}
+			a, b, c := s[face[0]].w, s[face[1]].w, s[face[2]].w
+			side := orientation(a, b, c, origin)
+			if !flat && (side == 0 || side == orientation(a, b, c, s[i].w)) {
+				continue
+			}
+
+			q, corners := closestPointOnTriangle(origin, a, b, c)
+			if d := q.Norm2(); d < bestD {
+				bestD, best = d, 0
+				for j, idx := range face {
+					if corners&(1<<uint(j)) != 0 {
+						best |= 1 << uint(idx)
+					}
+				}
+			}
+		}
+		if best == 0 {
+			return false
+		}
+		g.keep(best)
+
+	default:
+		g.lambdas = []float64{1}
+	}
+
+	return true
+}
+
+// Keeps the simplex vertices in the bit mask and computes the barycentric coordinates of the closest point to the origin.
+func (g *gjk) keep(mask uint) {
+	kept := g.simplex[:0]
+	for i, sv := range g.simplex {
+		if mask&(1<<uint(i)) != 0 {
+			kept = append(kept, sv)
+		}
+	}
+	g.simplex = kept
+
+	switch len(kept) {
+	case 1:
+		g.lambdas = []float64{1}
+
+	case 2:
+		ab := kept[1].w.Sub(kept[0].w)
+		t := -kept[0].w.Dot(ab) / ab.Norm2()
+		g.lambdas = []float64{1 - t, t}
+
+	default:
+		// Projection of the origin onto the plane of the triangle, see Ericson's "Real-Time Collision Detection", section 3.4
+		v0, v1 := kept[1].w.Sub(kept[0].w), kept[2].w.Sub(kept[0].w)
+		v2 := kept[0].w.Mul(-1)
+		d00, d01, d11 := v0.Dot(v0), v0.Dot(v1), v1.Dot(v1)
+		d20, d21 := v2.Dot(v0), v2.Dot(v1)
+		denom := d00*d11 - d01*d01
+		v := (d11*d20 - d01*d21) / denom
+		w := (d00*d21 - d01*d20) / denom
+		g.lambdas = []float64{1 - v - w, v, w}
+	}
+}
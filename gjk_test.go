@@ -0,0 +1,102 @@
+package quickhull
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestSupport(t *testing.T) {
+	pointCloud := randomPointCloud(1000)
+	hull := convexHull(pointCloud)
+	mesh := new(QuickHull).ConvexHullAsMesh(pointCloud, 0)
+	callerBuilt := ConvexHull{Vertices: hull.Vertices, Indices: hull.Indices, Dimension: DimensionPolytope}
+
+	for _, d := range randomPointCloud(200) {
+		expected := math.Inf(-1)
+		for _, p := range pointCloud {
+			expected = math.Max(expected, p.Dot(d))
+		}
+
+		assertEqual(t, expected, hull.Vertices[hull.Support(d)].Dot(d))
+		assertEqual(t, expected, mesh.SupportPoint(d).Dot(d))
+		assertEqual(t, expected, callerBuilt.SupportPoint(d).Dot(d))
+	}
+
+	square := convexHull([]r3.Vector{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0.5, Y: 0.5}})
+	assertEqual(t, r3.Vector{X: 1, Y: 1}, square.SupportPoint(r3.Vector{X: 1, Y: 1, Z: 1}))
+	assertEqual(t, -1, ConvexHull{}.Support(r3.Vector{X: 1}))
+	assertEqual(t, -1, ConvexHull{Dimension: DimensionPolytope}.Support(r3.Vector{X: 1}))
+}
+
+func TestDistanceCubes(t *testing.T) {
+	cube := convexHull(cubePointCloud(1))
+	moved := Transform{Rotation: IdentityTransform().Rotation, Translation: r3.Vector{X: 5, Y: 0.5}}
+
+	sep := Distance(cube, cube, nil, &moved)
+	assertEqual(t, false, sep.Overlap)
+	assertAlmostEqual(t, 3, sep.Distance, 1e-12)
+	assertAlmostEqual(t, 1, sep.PointA.X, 1e-12)
+	assertAlmostEqual(t, 4, sep.PointB.X, 1e-12)
+	assertAlmostEqual(t, sep.PointA.Y, sep.PointB.Y, 1e-12)
+	assertAlmostEqual(t, sep.PointA.Z, sep.PointB.Z, 1e-12)
+
+	// Rotated by 45 degrees around Z, the edge reaches sqrt(2) along X
+	c, s := math.Cos(math.Pi/4), math.Sin(math.Pi/4)
+	rotated := Transform{Rotation: [3][3]float64{{c, -s, 0}, {s, c, 0}, {0, 0, 1}}}
+	sep = Distance(cube, cube, &rotated, &moved)
+	assertAlmostEqual(t, 4-math.Sqrt2, sep.Distance, 1e-12)
+	assertAlmostEqual(t, math.Sqrt2, sep.PointA.X, 1e-12)
+
+	// Corner to corner
+	diagonal := Transform{Rotation: IdentityTransform().Rotation, Translation: r3.Vector{X: 3, Y: 3, Z: 3}}
+	sep = Distance(cube, cube, nil, &diagonal)
+	assertAlmostEqual(t, math.Sqrt(3), sep.Distance, 1e-12)
+	assertAlmostEqual(t, 0, sep.PointA.Sub(r3.Vector{X: 1, Y: 1, Z: 1}).Norm(), 1e-12)
+
+	overlapping := Transform{Rotation: rotated.Rotation, Translation: r3.Vector{X: 1.5, Y: 0.3, Z: -0.2}}
+	assertEqual(t, true, Overlap(cube, cube, nil, &overlapping))
+	assertEqual(t, true, Overlap(cube, cube, nil, nil))
+	assertEqual(t, false, Overlap(cube, cube, nil, &moved))
+
+	// Touching
+	touching := Transform{Rotation: IdentityTransform().Rotation, Translation: r3.Vector{X: 2, Y: 0.5}}
+	assertEqual(t, true, Overlap(cube, cube, nil, &touching))
+}
+
+func TestDistanceRandom(t *testing.T) {
+	a := convexHull(randomPointCloud(100))
+	b := new(QuickHull).ConvexHullAsMesh(randomPointCloud(100), 0)
+
+	overlaps := 0
+	for i := 0; i < 100; i++ {
+		offset := r3.Vector{X: randF64(-4, 4), Y: randF64(-4, 4), Z: randF64(-4, 4)}
+		transform := Transform{Rotation: IdentityTransform().Rotation, Translation: offset}
+		sep := Distance(a, b, nil, &transform)
+
+		if sep.Overlap {
+			overlaps++
+			continue
+		}
+
+		// The closest points are on the surfaces and the plane between them separates the shapes
+		assertAlmostEqual(t, sep.Distance, sep.PointB.Sub(sep.PointA).Norm(), 1e-9)
+		assertAlmostEqual(t, 0, a.SignedDistance(sep.PointA), 1e-9)
+		n := sep.PointB.Sub(sep.PointA).Normalize()
+		for _, v := range a.Vertices {
+			if v.Sub(sep.PointA).Dot(n) > 1e-9 {
+				t.Fatalf("vertex %v of A is beyond the closest point", v)
+			}
+		}
+		for _, v := range b.Vertices {
+			if transform.Apply(v).Sub(sep.PointB).Dot(n) < -1e-9 {
+				t.Fatalf("vertex %v of B is beyond the closest point", v)
+			}
+		}
+	}
+
+	if overlaps == 0 || overlaps == 100 {
+		t.Fatalf("%d of 100 queries overlap", overlaps)
+	}
+}
@@ -7,9 +7,18 @@ import (
 // HalfEdgeMesh is a mesh consisting of half edges.
 // See: https://www.openmesh.org/media/Documentations/OpenMesh-6.3-Documentation/a00010.html
 type HalfEdgeMesh struct {
-	Vertices  []r3.Vector
-	Faces     []Face
-	HalfEdges []HalfEdge
+	Vertices    []r3.Vector
+	Faces       []Face
+	HalfEdges   []HalfEdge
+	Normals     []r3.Vector // Unit normal (pointing out of the hull) of each Face, only set if Options.ComputeNormals is true
+	Epsilon     float64     // Tolerance used during the construction in units of the input, see ClosestPoint
+	Diagnostics Diagnostics // Statistics about the construction of the mesh
+
+	// Dimension of the point cloud. Meshes of points and segments have no Faces (except for QuickHull.ConvexHullAsMesh,
+	// which returns a degenerate tetrahedron for compatibility), planar meshes are closed (flat) triangle meshes.
+	Dimension Dimension
+	// Vertex indices of meshes that aren't polytopes: the point, the endpoints of the segment or the ordered boundary of the polygon.
+	Outline []int
 }
 
 // HalfEdge is a half edge.
@@ -37,8 +46,25 @@ type Face struct {
 	HalfEdge int // Index of a bounding HalfEdge
 }
 
-func newHalfEdgeMesh(builder meshBuilder, vertices []r3.Vector) HalfEdgeMesh {
-	var heMesh HalfEdgeMesh
+func newHalfEdgeMesh(builder meshBuilder, vertices []r3.Vector, opts Options, dim Dimension, outline []int) HalfEdgeMesh {
+	heMesh := HalfEdgeMesh{Dimension: dim}
+
+	useOriginalIndices := opts.IndexMode == OriginalIndices
+	if useOriginalIndices {
+		heMesh.Vertices = vertices
+	}
+
+	if dim < DimensionPolygon && !opts.legacy {
+		// No Faces, just the point or the endpoints of the segment
+		for i, v := range outline {
+			if !useOriginalIndices {
+				heMesh.Vertices = append(heMesh.Vertices, vertices[v])
+				v = i
+			}
+			heMesh.Outline = append(heMesh.Outline, v)
+		}
+		return heMesh
+	}
 
 	faceMapping := make(map[int]int)
 	halfEdgeMapping := make(map[int]int)
@@ -52,6 +78,15 @@ func newHalfEdgeMesh(builder meshBuilder, vertices []r3.Vector) HalfEdgeMesh {
 		heMesh.Faces = append(heMesh.Faces, Face{HalfEdge: f.halfEdgeIndex})
 		faceMapping[i] = len(heMesh.Faces) - 1
 
+		if opts.ComputeNormals {
+			v := builder.vertexIndicesOfFace(f)
+			heMesh.Normals = append(heMesh.Normals, triangleNormal(vertices[v[0]], vertices[v[1]], vertices[v[2]]).Normalize())
+		}
+
+		if useOriginalIndices {
+			continue
+		}
+
 		heIndicies := builder.halfEdgeIndicesOfFace(f)
 		for _, heIndex := range heIndicies {
 			vertexIndex := builder.halfEdges[heIndex].EndVertex
@@ -81,7 +116,18 @@ func newHalfEdgeMesh(builder meshBuilder, vertices []r3.Vector) HalfEdgeMesh {
 		heMesh.HalfEdges[i].Face = faceMapping[heMesh.HalfEdges[i].Face]
 		heMesh.HalfEdges[i].Opp = halfEdgeMapping[heMesh.HalfEdges[i].Opp]
 		heMesh.HalfEdges[i].Next = halfEdgeMapping[heMesh.HalfEdges[i].Next]
-		heMesh.HalfEdges[i].EndVertex = vertexMapping[heMesh.HalfEdges[i].EndVertex]
+		if !useOriginalIndices {
+			heMesh.HalfEdges[i].EndVertex = vertexMapping[heMesh.HalfEdges[i].EndVertex]
+		}
+	}
+
+	for _, v := range outline {
+		if !useOriginalIndices {
+			var found bool
+			v, found = vertexMapping[v]
+			assertTrue(found)
+		}
+		heMesh.Outline = append(heMesh.Outline, v)
 	}
 
 	return heMesh
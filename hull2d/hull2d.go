@@ -0,0 +1,161 @@
+// Package hull2d computes convex hulls of two dimensional point sets.
+//
+// It uses the same epsilon semantics as the three dimensional quickhull package: the epsilon is relative to the largest
+// absolute coordinate of the points, points closer than that to an edge of the hull are not part of the hull.
+package hull2d
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+// Hull is the convex hull of a two dimensional point set.
+type Hull struct {
+	// Dimension is DimensionPoint if all points coincide, DimensionSegment if they lie on a line and DimensionPolygon otherwise.
+	Dimension quickhull.Dimension
+	// Indices of the input points on the hull, counter-clockwise, starting with the point with the smallest X (and Y) coordinate.
+	// For segments these are the two endpoints, for points a single index.
+	Indices []int
+	Points  []r2.Point // Points of the hull, Points[i] is the input point Indices[i]
+}
+
+// ConvexHull computes the convex hull of the given points using Andrew's monotone chain algorithm.
+// If epsilon is <= 0 a default value will be used.
+// Returns an error of kind quickhull.KindDegenerateInput for empty input and quickhull.KindNonFiniteInput for NaN or infinite coordinates.
+func ConvexHull(points []r2.Point, epsilon float64) (Hull, error) {
+	if len(points) == 0 {
+		return Hull{}, &quickhull.Error{Kind: quickhull.KindDegenerateInput, Msg: "point set is empty"}
+	}
+
+	var scale float64
+	for i, p := range points {
+		if math.IsNaN(p.X) || math.IsInf(p.X, 0) || math.IsNaN(p.Y) || math.IsInf(p.Y, 0) {
+			return Hull{}, &quickhull.Error{Kind: quickhull.KindNonFiniteInput, Msg: fmt.Sprintf("point %d is %v", i, p)}
+		}
+		scale = math.Max(scale, math.Max(math.Abs(p.X), math.Abs(p.Y)))
+	}
+
+	if epsilon <= 0 {
+		epsilon = quickhull.DefaultEpsilon
+	}
+	epsilon *= scale
+
+	sorted := make([]int, len(points))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(points[sorted[i]], points[sorted[j]])
+	})
+
+	// Like quickhull.Classify, the point set is a single point if its extreme points are within epsilon of each other
+	a, b := extremePair(points, sorted[0], sorted[len(sorted)-1])
+	dir := points[b].Sub(points[a])
+	if dir.Norm() <= epsilon {
+		return newHull(points, quickhull.DimensionPoint, []int{sorted[0]}), nil
+	}
+
+	// And on a line if no point is farther than epsilon from the line through them
+	if maxLineDistance(points, points[a], dir) <= epsilon {
+		return newHull(points, quickhull.DimensionSegment, segmentEndpoints(points, dir)), nil
+	}
+
+	// Pops the last index of the chain as long as it isn't clearly to the right of the line between the one before and p.
+	// The chain then turns left at each of its points.
+	push := func(chain []int, i int) []int {
+		p := points[i]
+		for len(chain) >= 2 {
+			o, a := points[chain[len(chain)-2]], points[chain[len(chain)-1]]
+			if a.Sub(o).Cross(p.Sub(o)) > epsilon*p.Sub(o).Norm() {
+				break
+			}
+			chain = chain[:len(chain)-1]
+		}
+		return append(chain, i)
+	}
+
+	var lower []int
+	for _, i := range sorted {
+		lower = push(lower, i)
+	}
+	var upper []int
+	for k := len(sorted) - 1; k >= 0; k-- {
+		upper = push(upper, sorted[k])
+	}
+
+	// The last point of each chain is the first one of the other chain
+	indices := append(lower[:len(lower)-1], upper[:len(upper)-1]...)
+	if len(indices) < 3 {
+		return newHull(points, quickhull.DimensionSegment, segmentEndpoints(points, dir)), nil
+	}
+
+	return newHull(points, quickhull.DimensionPolygon, indices), nil
+}
+
+func less(a, b r2.Point) bool {
+	return a.X < b.X || (a.X == b.X && a.Y < b.Y)
+}
+
+// Returns the two most distant of the points with the smallest and largest X and Y coordinates.
+func extremePair(points []r2.Point, minX, maxX int) (int, int) {
+	minY, maxY := 0, 0
+	for i, p := range points {
+		if p.Y < points[minY].Y {
+			minY = i
+		}
+		if p.Y > points[maxY].Y {
+			maxY = i
+		}
+	}
+
+	extremes := [4]int{minX, maxX, minY, maxY}
+	a, b := minX, maxX
+	maxD := points[b].Sub(points[a]).Norm()
+	for i := range extremes {
+		for j := i + 1; j < len(extremes); j++ {
+			if d := points[extremes[j]].Sub(points[extremes[i]]).Norm(); d > maxD {
+				a, b, maxD = extremes[i], extremes[j], d
+			}
+		}
+	}
+	return a, b
+}
+
+// Returns the largest distance of the points to the line through o in direction dir.
+func maxLineDistance(points []r2.Point, o, dir r2.Point) float64 {
+	var maxD float64
+	for _, p := range points {
+		maxD = math.Max(maxD, math.Abs(dir.Cross(p.Sub(o))))
+	}
+	return maxD / dir.Norm()
+}
+
+// Returns the indices of the points farthest in and against direction dir, ordered like the points of the hull.
+func segmentEndpoints(points []r2.Point, dir r2.Point) []int {
+	lo, hi := 0, 0
+	for i, p := range points {
+		if p.Dot(dir) < points[lo].Dot(dir) {
+			lo = i
+		}
+		if p.Dot(dir) > points[hi].Dot(dir) {
+			hi = i
+		}
+	}
+	if less(points[hi], points[lo]) {
+		lo, hi = hi, lo
+	}
+	return []int{lo, hi}
+}
+
+func newHull(points []r2.Point, dim quickhull.Dimension, indices []int) Hull {
+	hull := Hull{Dimension: dim, Indices: indices}
+	for _, i := range indices {
+		hull.Points = append(hull.Points, points[i])
+	}
+	return hull
+}
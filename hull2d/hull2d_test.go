@@ -0,0 +1,84 @@
+package hull2d
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func TestConvexHullSquare(t *testing.T) {
+	points := []r2.Point{
+		{X: 0, Y: 10},
+		{X: 5, Y: 5}, // Inside
+		{X: 10, Y: 0},
+		{X: 0, Y: 5}, // On an edge
+		{X: 0, Y: 0},
+		{X: 10, Y: 10},
+	}
+
+	hull, err := ConvexHull(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, quickhull.DimensionPolygon, hull.Dimension)
+	assertEqual(t, []int{4, 2, 5, 0}, hull.Indices)
+	assertEqual(t, []r2.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}, hull.Points)
+}
+
+func TestConvexHullDegenerate(t *testing.T) {
+	hull, err := ConvexHull([]r2.Point{{X: 1, Y: 1}, {X: 1, Y: 1}}, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, quickhull.DimensionPoint, hull.Dimension)
+	assertEqual(t, []int{0}, hull.Indices)
+
+	hull, err = ConvexHull([]r2.Point{{X: 1, Y: 1}, {X: 3, Y: 3}, {X: 0, Y: 0}, {X: 2, Y: 2.0000000001}}, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, quickhull.DimensionSegment, hull.Dimension)
+	assertEqual(t, []int{2, 1}, hull.Indices)
+
+	// Nearly vertical, the first and last points in X order are close to each other
+	hull, err = ConvexHull([]r2.Point{{X: 0, Y: 0}, {X: 1e-9, Y: 5}, {X: 2e-9, Y: 0}}, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, quickhull.DimensionSegment, hull.Dimension)
+	assertEqual(t, []int{0, 1}, hull.Indices)
+
+	hull, err = ConvexHull([]r2.Point{{X: 0, Y: 0}, {X: 2e-9, Y: 3}, {X: 1e-9, Y: 6}}, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, quickhull.DimensionSegment, hull.Dimension)
+	assertEqual(t, []int{0, 2}, hull.Indices)
+
+	_, err = ConvexHull(nil, 0)
+	assertEqual(t, quickhull.KindDegenerateInput, err.(*quickhull.Error).Kind)
+}
+
+func TestConvexHullRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := make([]r2.Point, 1000)
+	for i := range points {
+		points[i] = r2.Point{X: r.NormFloat64(), Y: r.NormFloat64()}
+	}
+
+	hull, err := ConvexHull(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, quickhull.DimensionPolygon, hull.Dimension)
+
+	// Every point must be on the left of (or on) every edge
+	for i, a := range hull.Points {
+		b := hull.Points[(i+1)%len(hull.Points)]
+		for _, p := range points {
+			if b.Sub(a).Cross(p.Sub(a)) < -1e-9 {
+				t.Fatalf("point %v is outside of edge %v-%v", p, a, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package quickhull
+
+import (
+	"context"
+
+	"github.com/golang/geo/r3"
+)
+
+// Hull is a convex hull that can be extended with additional points.
+// Unlike QuickHull.ConvexHull, which builds the hull from scratch, AddPoints keeps the existing mesh and only processes new points that lie outside of it.
+// The result is the same as building the hull of all points at once.
+type Hull struct {
+	qh     QuickHull
+	opts   Options
+	points []r3.Vector
+	scale  float64
+}
+
+// NewHull calculates the convex hull of the given point cloud and returns it as a Hull that can be extended later.
+// The point cloud is copied. Errors are the same as for QuickHull.ConvexHullContext, the Hull is returned if the error comes with a usable result.
+func NewHull(pointCloud []r3.Vector, opts Options) (*Hull, error) {
+	h := &Hull{
+		opts:   opts,
+		points: append([]r3.Vector(nil), pointCloud...),
+	}
+
+	if len(pointCloud) == 0 {
+		// Nothing to do until points are added
+		return h, nil
+	}
+
+	err := h.rebuild(context.Background())
+	if err != nil && !isPartialResult(err) {
+		return nil, err
+	}
+
+	return h, err
+}
+
+// AddPoints adds the given points to the hull.
+func (h *Hull) AddPoints(points []r3.Vector) error {
+	return h.AddPointsContext(context.Background(), points)
+}
+
+// AddPointsContext adds the given points to the hull, stopping early if ctx is done or one of the budgets of the Options is exhausted.
+// In that case the hull doesn't contain all points yet, the next call to AddPoints(Context) will continue where it left off.
+func (h *Hull) AddPointsContext(ctx context.Context, points []r3.Vector) (err error) {
+	defer func() {
+		if e := errorFromPanic(recover()); e != nil {
+			err = e
+		}
+	}()
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	for i, v := range points {
+		if !isFinite(v) {
+			return newError(KindNonFiniteInput, "point %d is %v", i, v)
+		}
+	}
+
+	first := len(h.points)
+	h.points = append(h.points, points...)
+
+	// With a relative epsilon a larger scale changes the epsilon, which in turn may change the hull.
+	// The same goes for hulls that aren't proper (yet), those don't have the mesh required to add points,
+	// and normalized hulls, whose mesh is built from transformed points.
+	scaleGrew := h.opts.EpsilonMode == RelativeEpsilon && scale(points, extremeValues(points)) > h.scale
+	if scaleGrew || h.opts.Normalize || h.qh.dimension < DimensionPolytope || len(h.qh.mesh.faces) == 0 {
+		return h.rebuild(ctx)
+	}
+
+	h.qh.vertexData = h.points
+
+	err = h.qh.extendMesh(ctx, first, h.opts)
+	if h.opts.Joggle && isErrorKind(err, KindHorizonFailure) {
+		// The points of the failed horizon edges were dropped, retry with joggled input like a full build does
+		return h.rebuild(ctx)
+	}
+
+	return err
+}
+
+func (h *Hull) rebuild(ctx context.Context) error {
+	if len(h.points) > 0 {
+		h.scale = scale(h.points, extremeValues(h.points))
+	}
+
+	return h.qh.build(ctx, h.points, h.opts)
+}
+
+// Points returns all points that were added to the hull so far.
+// The returned slice must not be modified.
+func (h *Hull) Points() []r3.Vector {
+	return h.points
+}
+
+// ConvexHull returns the current hull as ConvexHull.
+func (h *Hull) ConvexHull() ConvexHull {
+	hull := newConvexHull(h.qh.mesh, h.qh.vertexData, h.opts, h.qh.dimension, h.qh.outline)
+	hull.Diagnostics = h.qh.diagnostics
+	hull.Epsilon = h.qh.epsilon * h.qh.unit
+	return hull
+}
+
+// Mesh returns the current hull as HalfEdgeMesh.
+func (h *Hull) Mesh() HalfEdgeMesh {
+	mesh := newHalfEdgeMesh(h.qh.mesh, h.qh.vertexData, h.opts, h.qh.dimension, h.qh.outline)
+	mesh.Diagnostics = h.qh.diagnostics
+	mesh.Epsilon = h.qh.epsilon * h.qh.unit
+	return mesh
+}
@@ -0,0 +1,111 @@
+package quickhull
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestHullAddPoints(t *testing.T) {
+	pointCloud := randomPointCloud(2000)
+
+	for _, mode := range []EpsilonMode{RelativeEpsilon, AbsoluteEpsilon} {
+		opts := Options{EpsilonMode: mode}
+
+		h, err := NewHull(pointCloud[:100], opts)
+		assertEqual(t, nil, err)
+
+		for i := 100; i < len(pointCloud); i += 300 {
+			end := i + 300
+			if end > len(pointCloud) {
+				end = len(pointCloud)
+			}
+
+			err = h.AddPoints(pointCloud[i:end])
+			assertEqual(t, nil, err)
+
+			expected, err := new(QuickHull).TryConvexHull(pointCloud[:end], opts)
+			assertEqual(t, nil, err)
+
+			actual := h.ConvexHull()
+			assertElementsMatch(t, expected.Vertices, actual.Vertices, "incremental hull should match batch hull")
+			assertClosedMesh(t, actual)
+		}
+	}
+}
+
+func TestHullAddPointsIncrementally(t *testing.T) {
+	// The initial points span the whole range, so the scale (and thus the epsilon) doesn't change and the mesh is extended instead of rebuilt
+	pointCloud := append([]r3.Vector{
+		{X: -1, Y: -1, Z: -1},
+		{X: 1, Y: 1, Z: 1},
+	}, randomPointCloud(1000)...)
+
+	h, err := NewHull(pointCloud[:500], Options{})
+	assertEqual(t, nil, err)
+
+	iterations := h.ConvexHull().Diagnostics.Iterations
+
+	err = h.AddPoints(pointCloud[500:])
+	assertEqual(t, nil, err)
+
+	hull := h.ConvexHull()
+	if hull.Diagnostics.Iterations <= iterations {
+		t.Errorf("expected diagnostics to accumulate, got %d iterations after %d", hull.Diagnostics.Iterations, iterations)
+	}
+
+	assertElementsMatch(t, convexHull(pointCloud).Vertices, hull.Vertices, "incremental hull should match batch hull")
+	assertEqual(t, len(pointCloud), len(h.Points()))
+}
+
+func TestHullAddPointsFromDegenerate(t *testing.T) {
+	h, err := NewHull(nil, Options{})
+	assertEqual(t, nil, err)
+
+	square := []r3.Vector{
+		{X: 0, Y: 0, Z: 0},
+		{X: 1, Y: 0, Z: 0},
+		{X: 0, Y: 1, Z: 0},
+		{X: 1, Y: 1, Z: 0},
+	}
+	err = h.AddPoints(square)
+	assertEqual(t, nil, err)
+	assertElementsMatch(t, square, h.ConvexHull().Vertices)
+
+	err = h.AddPoints([]r3.Vector{{X: 0.5, Y: 0.5, Z: 1}})
+	assertEqual(t, nil, err)
+	assertEqual(t, 5, len(h.ConvexHull().Vertices))
+	assertEqual(t, 6, len(h.Mesh().Faces))
+}
+
+func TestHullAddPointsJoggle(t *testing.T) {
+	// Extending the hull of the first 100 points fails to solve a horizon edge without joggling
+	pointCloud := horizonFailurePointCloud()
+	opts := Options{Epsilon: 1e-14, Joggle: true, JoggleSeed: 42}
+
+	h, err := NewHull(pointCloud[:100], opts)
+	assertEqual(t, nil, err)
+
+	err = h.AddPoints(pointCloud[100:])
+	assertEqual(t, nil, err)
+
+	hull := h.ConvexHull()
+	assertEqual(t, 0, hull.Diagnostics.FailedHorizonEdges)
+	if hull.Diagnostics.JoggleAttempts == 0 {
+		t.Errorf("expected the hull to be rebuilt with joggled input, got %+v", hull.Diagnostics)
+	}
+	assertClosedMesh(t, hull)
+
+	// The planes must go through the original points so further points are tested against the actual hull
+	for _, f := range h.qh.mesh.faces {
+		if f.isDisabled() {
+			continue
+		}
+		for _, v := range h.qh.mesh.vertexIndicesOfFace(f) {
+			if d := math.Abs(signedDistanceToPlane(pointCloud[v], f.plane)); d > 1e-13 {
+				t.Errorf("vertex %d is %g away from the plane of its face", v, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package paraboloid
+
+import (
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+// Distance of the ghost sites from the center, relative to the radius of the region of interest.
+// Must be more than 3 so no point of the region is closer to a ghost than to every real site.
+const ghostDistance = 4
+
+// Ghosts returns four sites surrounding the points and bounds far enough that they don't affect the cells of the points
+// inside bounds. Adding them makes every cell of the points bounded, i.e. all lifted points are strictly inside the lower hull.
+func Ghosts(points []r2.Point, bounds r2.Rect) []r2.Point {
+	region := r2.RectFromPoints(points...).Union(bounds)
+	center := region.Center()
+	radius := region.Size().Norm() / 2
+	if radius == 0 {
+		radius = 1
+	}
+
+	var ghosts []r2.Point
+	for _, corner := range []r2.Point{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: 1, Y: 1}, {X: -1, Y: 1}} {
+		ghosts = append(ghosts, center.Add(corner.Mul(ghostDistance*radius)))
+	}
+	return ghosts
+}
+
+// Cells returns the cells of the first n lifted points of the mesh, clipped to bounds. The mesh must have been built with
+// OriginalIndices and ComputeNormals and the first n points must be strictly inside the lower hull (see Ghosts).
+// The vertex of each lower face is computed by vertex from the indices of its corners.
+// Points that aren't vertices of the mesh get no cell.
+func Cells(mesh quickhull.HalfEdgeMesh, n int, vertex func(a, b, c int) r2.Point, bounds r2.Rect) [][]r2.Point {
+	// Any half edge ending at the point
+	incoming := make([]int, n)
+	for i := range incoming {
+		incoming[i] = -1
+	}
+	for i, he := range mesh.HalfEdges {
+		if he.EndVertex < n {
+			incoming[he.EndVertex] = i
+		}
+	}
+
+	vertices := make([]r2.Point, len(mesh.Faces))
+	for i, f := range mesh.Faces {
+		if mesh.Normals[i].Z >= 0 {
+			continue
+		}
+		a := mesh.HalfEdges[f.HalfEdge]
+		b := mesh.HalfEdges[a.Next]
+		c := mesh.HalfEdges[b.Next]
+		vertices[i] = vertex(a.EndVertex, b.EndVertex, c.EndVertex)
+	}
+
+	cells := make([][]r2.Point, n)
+	for i, start := range incoming {
+		if start < 0 {
+			continue
+		}
+
+		// Walk around the point through the faces sharing it
+		var cell []r2.Point
+		for h := start; ; {
+			cell = append(cell, vertices[mesh.HalfEdges[h].Face])
+			h = mesh.HalfEdges[mesh.HalfEdges[h].Next].Opp
+			if h == start || len(cell) > len(mesh.Faces) {
+				break
+			}
+		}
+
+		cells[i] = Clip(counterClockwise(dedup(cell)), bounds)
+	}
+
+	return cells
+}
+
+// Removes consecutive duplicates, which are caused by cocircular points.
+func dedup(polygon []r2.Point) []r2.Point {
+	out := polygon[:0]
+	for i, p := range polygon {
+		if i == 0 || p != out[len(out)-1] {
+			out = append(out, p)
+		}
+	}
+	for len(out) > 1 && out[0] == out[len(out)-1] {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+func counterClockwise(polygon []r2.Point) []r2.Point {
+	var area float64
+	for i, p := range polygon {
+		area += p.Cross(polygon[(i+1)%len(polygon)])
+	}
+	if area < 0 {
+		for i, j := 0, len(polygon)-1; i < j; i, j = i+1, j-1 {
+			polygon[i], polygon[j] = polygon[j], polygon[i]
+		}
+	}
+	return polygon
+}
+
+// Clip clips the convex, counter-clockwise polygon to the rectangle (Sutherland–Hodgman).
+func Clip(polygon []r2.Point, bounds r2.Rect) []r2.Point {
+	// Half planes of the rectangle: normal . p <= offset
+	edges := [4]struct {
+		normal r2.Point
+		offset float64
+	}{
+		{r2.Point{X: -1}, -bounds.X.Lo},
+		{r2.Point{X: 1}, bounds.X.Hi},
+		{r2.Point{Y: -1}, -bounds.Y.Lo},
+		{r2.Point{Y: 1}, bounds.Y.Hi},
+	}
+
+	for _, e := range edges {
+		if len(polygon) == 0 {
+			break
+		}
+		var out []r2.Point
+		for i, p := range polygon {
+			q := polygon[(i+1)%len(polygon)]
+			dp := e.normal.Dot(p) - e.offset
+			dq := e.normal.Dot(q) - e.offset
+			if dp <= 0 {
+				out = append(out, p)
+			}
+			if (dp < 0 && dq > 0) || (dp > 0 && dq < 0) {
+				out = append(out, p.Add(q.Sub(p).Mul(dp/(dp-dq))))
+			}
+		}
+		polygon = out
+	}
+
+	return polygon
+}
@@ -0,0 +1,63 @@
+// Package paraboloid lifts two dimensional points onto the paraboloid z = x² + y², which turns Delaunay triangulations,
+// Voronoi diagrams and power diagrams into lower convex hulls.
+package paraboloid
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+// Transform maps points into the unit box around the origin. Normalizing before lifting keeps the lifted coordinates
+// in the same range for all inputs.
+type Transform struct {
+	Center r2.Point
+	Unit   float64
+}
+
+// NewTransform returns the transform that maps the bounding box of the points into the unit box.
+// Returns an error of kind quickhull.KindNonFiniteInput if any of the points has NaN or infinite coordinates.
+func NewTransform(points []r2.Point) (Transform, error) {
+	bounds := r2.EmptyRect()
+	for i, p := range points {
+		if math.IsNaN(p.X) || math.IsInf(p.X, 0) || math.IsNaN(p.Y) || math.IsInf(p.Y, 0) {
+			return Transform{}, &quickhull.Error{Kind: quickhull.KindNonFiniteInput, Msg: fmt.Sprintf("point %d is %v", i, p)}
+		}
+		bounds = bounds.AddPoint(p)
+	}
+
+	unit := math.Max(bounds.Size().X, bounds.Size().Y) / 2
+	if unit == 0 {
+		unit = 1
+	}
+
+	return Transform{Center: bounds.Center(), Unit: unit}, nil
+}
+
+// Apply maps p into the unit box.
+func (t Transform) Apply(p r2.Point) r2.Point {
+	return p.Sub(t.Center).Mul(1 / t.Unit)
+}
+
+// Invert maps p from the unit box back to the original coordinates.
+func (t Transform) Invert(p r2.Point) r2.Point {
+	return p.Mul(t.Unit).Add(t.Center)
+}
+
+// Lift normalizes the points with t and lifts them onto the paraboloid, lowered by the weights (if not nil).
+// The weights are in squared units of the original coordinates, like the squared radii of a power diagram.
+func (t Transform) Lift(points []r2.Point, weights []float64) []r3.Vector {
+	lifted := make([]r3.Vector, len(points))
+	for i, p := range points {
+		q := t.Apply(p)
+		lifted[i] = r3.Vector{X: q.X, Y: q.Y, Z: q.X*q.X + q.Y*q.Y}
+		if weights != nil {
+			lifted[i].Z -= weights[i] / (t.Unit * t.Unit)
+		}
+	}
+	return lifted
+}
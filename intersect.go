@@ -0,0 +1,80 @@
+package quickhull
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// Intersection is the part of a line origin + t * dir that is inside a ConvexHull: Enter <= t <= Exit.
+type Intersection struct {
+	Enter, Exit             float64   // Parameters where the line enters and leaves the hull
+	EnterFace, ExitFace     int       // Triangles that are hit, -1 if the ray / segment already starts or ends inside the hull
+	EnterNormal, ExitNormal r3.Vector // Unit normals (pointing out of the hull) of the faces that are hit, zero if there is no such face
+}
+
+// IntersectLine intersects the line through origin in direction dir with the hull.
+// The line is clipped against the planes of the faces (Cyrus–Beck), so this takes O(faces) without any triangle tests.
+// Returns false if the line misses the hull. Hulls that aren't polytopes have no volume and are never hit.
+func (hull ConvexHull) IntersectLine(origin, dir r3.Vector) (Intersection, bool) {
+	return hull.clip(origin, dir, math.Inf(-1), math.Inf(1))
+}
+
+// IntersectRay intersects the ray starting at origin in direction dir with the hull, see IntersectLine.
+// Enter is 0 and EnterFace is -1 if origin is inside the hull.
+func (hull ConvexHull) IntersectRay(origin, dir r3.Vector) (Intersection, bool) {
+	return hull.clip(origin, dir, 0, math.Inf(1))
+}
+
+// IntersectSegment intersects the segment from a to b with the hull, see IntersectLine.
+// The parameters are relative to the segment, i.e. 0 at a and 1 at b.
+func (hull ConvexHull) IntersectSegment(a, b r3.Vector) (Intersection, bool) {
+	return hull.clip(a, b.Sub(a), 0, 1)
+}
+
+// Clips origin + t * dir with tMin <= t <= tMax against the halfspaces of the faces.
+func (hull ConvexHull) clip(origin, dir r3.Vector, tMin, tMax float64) (Intersection, bool) {
+	if hull.Dimension < DimensionPolytope {
+		return Intersection{}, false
+	}
+	planes := hull.Planes()
+	if len(planes) == 0 {
+		return Intersection{}, false
+	}
+
+	res := Intersection{Enter: tMin, Exit: tMax, EnterFace: -1, ExitFace: -1}
+	for i, pl := range planes {
+		dist := pl.SignedDistance(origin)
+		denom := pl.Normal.Dot(dir)
+		if denom == 0 {
+			// Parallel to the plane
+			if dist > 0 {
+				return Intersection{}, false
+			}
+			continue
+		}
+
+		t := -dist / denom
+		if denom < 0 {
+			// Entering the halfspace
+			if t > res.Enter {
+				res.Enter, res.EnterFace = t, i
+			}
+		} else if t < res.Exit {
+			res.Exit, res.ExitFace = t, i
+		}
+
+		if res.Enter > res.Exit {
+			return Intersection{}, false
+		}
+	}
+
+	if res.EnterFace >= 0 {
+		res.EnterNormal = planes[res.EnterFace].Normal
+	}
+	if res.ExitFace >= 0 {
+		res.ExitNormal = planes[res.ExitFace].Normal
+	}
+
+	return res, true
+}
@@ -0,0 +1,88 @@
+package quickhull
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestIntersectRay(t *testing.T) {
+	hull := convexHull(cubePointCloud(1))
+
+	res, hit := hull.IntersectRay(r3.Vector{X: -5, Y: 0.1, Z: 0.2}, r3.Vector{X: 1})
+	assertEqual(t, true, hit)
+	assertAlmostEqual(t, 4, res.Enter, 1e-12)
+	assertAlmostEqual(t, 6, res.Exit, 1e-12)
+	assertEqual(t, r3.Vector{X: -1}, res.EnterNormal)
+	assertEqual(t, r3.Vector{X: 1}, res.ExitNormal)
+	assertEqual(t, hull.Planes()[res.EnterFace].Normal, res.EnterNormal)
+
+	// Starting inside
+	res, hit = hull.IntersectRay(r3.Vector{X: 0.5}, r3.Vector{Y: 2})
+	assertEqual(t, true, hit)
+	assertEqual(t, 0.0, res.Enter)
+	assertEqual(t, -1, res.EnterFace)
+	assertEqual(t, r3.Vector{}, res.EnterNormal)
+	assertAlmostEqual(t, 0.5, res.Exit, 1e-12)
+	assertEqual(t, r3.Vector{Y: 1}, res.ExitNormal)
+
+	// Pointing away, parallel outside
+	_, hit = hull.IntersectRay(r3.Vector{X: 3}, r3.Vector{X: 1})
+	assertEqual(t, false, hit)
+	_, hit = hull.IntersectRay(r3.Vector{X: 3}, r3.Vector{Y: 1})
+	assertEqual(t, false, hit)
+	_, hit = hull.IntersectRay(r3.Vector{X: 3, Y: 3}, r3.Vector{X: -1, Y: 0.1})
+	assertEqual(t, false, hit)
+}
+
+func TestIntersectLineAndSegment(t *testing.T) {
+	hull := convexHull(cubePointCloud(1))
+
+	res, hit := hull.IntersectLine(r3.Vector{X: 3}, r3.Vector{X: 1})
+	assertEqual(t, true, hit)
+	assertAlmostEqual(t, -4, res.Enter, 1e-12)
+	assertAlmostEqual(t, -2, res.Exit, 1e-12)
+
+	res, hit = hull.IntersectSegment(r3.Vector{X: -3}, r3.Vector{X: 0.5})
+	assertEqual(t, true, hit)
+	assertAlmostEqual(t, 2.0/3.5, res.Enter, 1e-12)
+	assertEqual(t, 1.0, res.Exit)
+	assertEqual(t, -1, res.ExitFace)
+
+	_, hit = hull.IntersectSegment(r3.Vector{X: -3}, r3.Vector{X: -2})
+	assertEqual(t, false, hit)
+
+	_, hit = convexHull([]r3.Vector{{X: 0}, {X: 1}, {Y: 1}}).IntersectLine(r3.Vector{Z: 1}, r3.Vector{Z: -1})
+	assertEqual(t, false, hit)
+}
+
+func TestIntersectRandom(t *testing.T) {
+	hull := convexHull(randomPointCloud(200))
+
+	hits := 0
+	for _, origin := range randomPointCloud(300) {
+		origin = origin.Mul(3)
+		dir := r3.Vector{X: randF64(-1, 1), Y: randF64(-1, 1), Z: randF64(-1, 1)}
+
+		res, hit := hull.IntersectLine(origin, dir)
+		if !hit {
+			// Must miss all sample points along the line
+			for s := -10.0; s <= 10; s += 0.05 {
+				if hull.Locate(origin.Add(dir.Mul(s))) == LocationInside {
+					t.Fatalf("line through %v in direction %v misses the hull at %v", origin, dir, s)
+				}
+			}
+			continue
+		}
+		hits++
+
+		assertEqual(t, LocationInside, hull.Locate(origin.Add(dir.Mul((res.Enter+res.Exit)/2))))
+		assertAlmostEqual(t, 0, hull.SignedDistance(origin.Add(dir.Mul(res.Enter))), 1e-9)
+		assertAlmostEqual(t, 0, hull.SignedDistance(origin.Add(dir.Mul(res.Exit))), 1e-9)
+		assertAlmostEqual(t, 0, hull.Planes()[res.EnterFace].SignedDistance(origin.Add(dir.Mul(res.Enter))), 1e-9)
+	}
+
+	if hits == 0 {
+		t.Fatal("no line hit the hull")
+	}
+}
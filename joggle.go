@@ -0,0 +1,68 @@
+package quickhull
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/golang/geo/r3"
+)
+
+const (
+	initialJoggle     = 1e-11 // Initial joggle magnitude, relative to the scale of the point cloud
+	joggleGrowth      = 10    // Factor by which the joggle magnitude grows with each attempt
+	maxJoggleAttempts = 5
+)
+
+// Builds the mesh and, if enabled and the horizon edge couldn't be solved for some points, retries with randomly perturbed ('joggled') input.
+// The mesh always refers to the original point cloud, so the output contains the original coordinates.
+func (qh *QuickHull) build(ctx context.Context, pointCloud []r3.Vector, opts Options) error {
+	err := qh.buildMesh(ctx, pointCloud, opts)
+	if !opts.Joggle || !isErrorKind(err, KindHorizonFailure) {
+		return err
+	}
+
+	r := rand.New(rand.NewSource(opts.JoggleSeed))
+	magnitude := initialJoggle * scale(pointCloud, extremeValues(pointCloud))
+	joggled := make([]r3.Vector, len(pointCloud))
+
+	for attempt := 1; attempt <= maxJoggleAttempts; attempt++ {
+		for i, v := range pointCloud {
+			joggled[i] = r3.Vector{
+				X: v.X + magnitude*(2*r.Float64()-1),
+				Y: v.Y + magnitude*(2*r.Float64()-1),
+				Z: v.Z + magnitude*(2*r.Float64()-1),
+			}
+		}
+
+		err = qh.buildMesh(ctx, joggled, opts)
+		qh.diagnostics.JoggleAttempts = attempt
+		qh.diagnostics.JoggleMagnitude = magnitude
+
+		if !isErrorKind(err, KindHorizonFailure) {
+			break
+		}
+
+		magnitude *= joggleGrowth
+	}
+
+	qh.vertexData = pointCloud
+	if qh.dimension == DimensionPolytope {
+		// The planes are used when points are added later, those must be tested against the original points
+		qh.recomputePlanes()
+	}
+
+	return err
+}
+
+// Computes the plane of each Face from the current vertex data.
+func (qh *QuickHull) recomputePlanes() {
+	for i := range qh.mesh.faces {
+		f := &qh.mesh.faces[i]
+		if f.isDisabled() {
+			continue
+		}
+		v := qh.mesh.vertexIndicesOfFace(*f)
+		va := qh.vertexData[v[0]]
+		f.plane = newPlane(triangleNormal(va, qh.vertexData[v[1]], qh.vertexData[v[2]]), va)
+	}
+}
@@ -0,0 +1,223 @@
+package quickhull
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// MassProperties of a solid hull with uniform density.
+type MassProperties struct {
+	Volume   float64
+	Mass     float64       // Volume * density
+	Centroid r3.Vector     // Center of mass
+	Inertia  [3][3]float64 // Inertia tensor relative to the centroid, rows / columns are X, Y and Z
+}
+
+// MassProperties returns volume, mass, center of mass and inertia tensor of the hull as a solid of the given density.
+// The values are computed exactly (up to rounding) by decomposing the hull into tetrahedra spanned by its triangles.
+// Hulls that aren't polytopes have no volume, mass or inertia, only the Centroid is set.
+func (hull ConvexHull) MassProperties(density float64) MassProperties {
+	if hull.Dimension < DimensionPolytope {
+		return MassProperties{Centroid: outlineCentroid(hull.Vertices, hull.Outline, hull.Polygon)}
+	}
+	return massProperties(hull, density)
+}
+
+// Volume returns the volume of the hull, 0 if it isn't a polytope.
+func (hull ConvexHull) Volume() float64 {
+	if hull.Dimension < DimensionPolytope {
+		return 0
+	}
+	return volume(hull)
+}
+
+// SurfaceArea returns the area of the surface of the hull.
+// The surface of a planar hull consists of both sides of the polygon, so it's twice the area of the polygon.
+func (hull ConvexHull) SurfaceArea() float64 {
+	if hull.Dimension < DimensionPolytope {
+		return 2 * polygonArea(hull.Polygon)
+	}
+	return surfaceArea(hull)
+}
+
+// Centroid returns the center of mass of the hull as a solid of uniform density.
+// For hulls that aren't polytopes, this is the centroid of the polygon, the center of the segment or the point.
+func (hull ConvexHull) Centroid() r3.Vector {
+	return hull.MassProperties(1).Centroid
+}
+
+// MassProperties returns volume, mass, center of mass and inertia tensor of the mesh, see ConvexHull.MassProperties.
+func (mesh HalfEdgeMesh) MassProperties(density float64) MassProperties {
+	if mesh.Dimension < DimensionPolytope {
+		return MassProperties{Centroid: outlineCentroid(mesh.Vertices, mesh.Outline, mesh.polygon())}
+	}
+	return massProperties(mesh, density)
+}
+
+// Volume returns the volume of the mesh, 0 if it isn't a polytope.
+func (mesh HalfEdgeMesh) Volume() float64 {
+	if mesh.Dimension < DimensionPolytope {
+		return 0
+	}
+	return volume(mesh)
+}
+
+// SurfaceArea returns the area of the surface of the mesh, see ConvexHull.SurfaceArea.
+func (mesh HalfEdgeMesh) SurfaceArea() float64 {
+	if mesh.Dimension < DimensionPolytope {
+		return 2 * polygonArea(mesh.polygon())
+	}
+	return surfaceArea(mesh)
+}
+
+// Centroid returns the center of mass of the mesh, see ConvexHull.Centroid.
+func (mesh HalfEdgeMesh) Centroid() r3.Vector {
+	return mesh.MassProperties(1).Centroid
+}
+
+// Returns the polygon of a planar mesh, nil for other dimensions.
+func (mesh HalfEdgeMesh) polygon() *Polygon {
+	if mesh.Dimension != DimensionPolygon {
+		return nil
+	}
+	_, poly := newPolygon(mesh.Vertices, mesh.Outline, true)
+	return poly
+}
+
+// Returns the corners of the triangle ordered so its right-hand normal points out of the hull.
+func outwardTriangle(m triangleMesh, i int) (r3.Vector, r3.Vector, r3.Vector) {
+	v := m.triangle(i)
+	a, b, c := m.vertex(v[0]), m.vertex(v[1]), m.vertex(v[2])
+	if triangleNormal(a, b, c).Dot(m.plane(i).Normal) < 0 {
+		b, c = c, b
+	}
+	return a, b, c
+}
+
+func volume(m triangleMesh) float64 {
+	if m.triangleCount() == 0 {
+		return 0
+	}
+
+	// Tetrahedra spanned by the triangles and a vertex of the hull rather than the origin, which may be far away
+	ref := m.vertex(m.triangle(0)[0])
+	var vol float64
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := outwardTriangle(m, i)
+		vol += a.Sub(ref).Dot(b.Sub(ref).Cross(c.Sub(ref)))
+	}
+	return vol / 6
+}
+
+func surfaceArea(m triangleMesh) float64 {
+	var area float64
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := outwardTriangle(m, i)
+		area += triangleNormal(a, b, c).Norm()
+	}
+	return area / 2
+}
+
+// Integrates over the tetrahedra spanned by the triangles and a vertex of the hull.
+// The covariance of each tetrahedron is derived from the covariance of the canonical tetrahedron, see Blow and Binstock's
+// "How to find the inertia tensor (or other mass properties) of a 3D solid body represented by a triangle mesh".
+func massProperties(m triangleMesh, density float64) MassProperties {
+	if m.triangleCount() == 0 {
+		return MassProperties{}
+	}
+
+	ref := m.vertex(m.triangle(0)[0])
+	var (
+		det6       float64       // Six times the volume
+		weighted   r3.Vector     // Sum of det * (a + b + c)
+		covariance [3][3]float64 // Integral of x * x^T over the hull, relative to ref
+	)
+	for i := 0; i < m.triangleCount(); i++ {
+		a, b, c := outwardTriangle(m, i)
+		a, b, c = a.Sub(ref), b.Sub(ref), c.Sub(ref)
+		det := a.Dot(b.Cross(c))
+		s := a.Add(b).Add(c)
+
+		det6 += det
+		weighted = weighted.Add(s.Mul(det))
+
+		av, bv, cv, sv := vectorArray(a), vectorArray(b), vectorArray(c), vectorArray(s)
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				covariance[j][k] += det / 120 * (av[j]*av[k] + bv[j]*bv[k] + cv[j]*cv[k] + sv[j]*sv[k])
+			}
+		}
+	}
+
+	vol := det6 / 6
+	if vol == 0 {
+		return MassProperties{Centroid: ref}
+	}
+	centroid := weighted.Mul(1 / (4 * det6))
+
+	// Move the covariance to the centroid and turn it into the inertia tensor
+	cv := vectorArray(centroid)
+	for j := 0; j < 3; j++ {
+		for k := 0; k < 3; k++ {
+			covariance[j][k] -= vol * cv[j] * cv[k]
+		}
+	}
+	trace := covariance[0][0] + covariance[1][1] + covariance[2][2]
+
+	props := MassProperties{
+		Volume:   vol,
+		Mass:     vol * density,
+		Centroid: ref.Add(centroid),
+	}
+	for j := 0; j < 3; j++ {
+		for k := 0; k < 3; k++ {
+			props.Inertia[j][k] = -density * covariance[j][k]
+		}
+		props.Inertia[j][j] += density * trace
+	}
+	return props
+}
+
+func vectorArray(v r3.Vector) [3]float64 {
+	return [3]float64{v.X, v.Y, v.Z}
+}
+
+// Returns the centroid of a hull that isn't a polytope: the centroid of the polygon, the center of the segment or the point.
+func outlineCentroid(vertices []r3.Vector, outline []int, poly *Polygon) r3.Vector {
+	if poly != nil {
+		// Fan triangulation weighted by area
+		var centroid r3.Vector
+		var area float64
+		o := poly.Vertices[0]
+		for i := 1; i+1 < len(poly.Vertices); i++ {
+			a, b := poly.Vertices[i], poly.Vertices[i+1]
+			w := triangleNormal(a, b, o).Norm()
+			centroid = centroid.Add(o.Add(a).Add(b).Mul(w / 3))
+			area += w
+		}
+		if area > 0 {
+			return centroid.Mul(1 / area)
+		}
+	}
+
+	var centroid r3.Vector
+	for _, v := range outline {
+		centroid = centroid.Add(vertices[v])
+	}
+	return centroid.Mul(1 / math.Max(1, float64(len(outline))))
+}
+
+// Returns the area of the polygon, 0 for nil.
+func polygonArea(poly *Polygon) float64 {
+	if poly == nil {
+		return 0
+	}
+
+	var area float64
+	o := poly.Vertices[0]
+	for i := 1; i+1 < len(poly.Vertices); i++ {
+		area += triangleNormal(poly.Vertices[i], poly.Vertices[i+1], o).Norm()
+	}
+	return area / 2
+}
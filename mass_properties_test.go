@@ -0,0 +1,95 @@
+package quickhull
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func assertInertia(t *testing.T, expected, actual [3][3]float64, delta float64) {
+	t.Helper()
+
+	for j := 0; j < 3; j++ {
+		for k := 0; k < 3; k++ {
+			assertAlmostEqual(t, expected[j][k], actual[j][k], delta)
+		}
+	}
+}
+
+func TestMassPropertiesCube(t *testing.T) {
+	hull := convexHull(cubePointCloud(1))
+
+	props := hull.MassProperties(1)
+	assertAlmostEqual(t, 8, props.Volume, 1e-12)
+	assertAlmostEqual(t, 8, props.Mass, 1e-12)
+	assertAlmostEqual(t, 0, props.Centroid.Norm(), 1e-12)
+	i := 8 * (4 + 4) / 12.0
+	assertInertia(t, [3][3]float64{{i, 0, 0}, {0, i, 0}, {0, 0, i}}, props.Inertia, 1e-12)
+
+	assertAlmostEqual(t, 8, hull.Volume(), 1e-12)
+	assertAlmostEqual(t, 24, hull.SurfaceArea(), 1e-12)
+	assertAlmostEqual(t, 0, hull.Centroid().Norm(), 1e-12)
+}
+
+func TestMassPropertiesBox(t *testing.T) {
+	// 2 x 4 x 6 box centered at (1, 2, 3)
+	var pointCloud []r3.Vector
+	for _, x := range []float64{0, 2} {
+		for _, y := range []float64{0, 4} {
+			for _, z := range []float64{0, 6} {
+				pointCloud = append(pointCloud, r3.Vector{X: x, Y: y, Z: z})
+			}
+		}
+	}
+	for _, p := range randomPointCloud(100) {
+		pointCloud = append(pointCloud, r3.Vector{X: 1 + p.X, Y: 2 + 2*p.Y, Z: 3 + 3*p.Z})
+	}
+
+	// Both windings must give the same result
+	for _, ccw := range []bool{true, false} {
+		hull := new(QuickHull).ConvexHull(pointCloud, ccw, false, 0)
+
+		props := hull.MassProperties(2)
+		assertAlmostEqual(t, 48, props.Volume, 1e-10)
+		assertAlmostEqual(t, 96, props.Mass, 1e-10)
+		assertAlmostEqual(t, 0, props.Centroid.Sub(r3.Vector{X: 1, Y: 2, Z: 3}).Norm(), 1e-12)
+		assertInertia(t, [3][3]float64{{416, 0, 0}, {0, 320, 0}, {0, 0, 160}}, props.Inertia, 1e-9)
+		assertAlmostEqual(t, 88, hull.SurfaceArea(), 1e-10)
+	}
+
+	mesh := new(QuickHull).ConvexHullAsMesh(pointCloud, 0)
+	props := mesh.MassProperties(2)
+	assertAlmostEqual(t, 48, mesh.Volume(), 1e-10)
+	assertAlmostEqual(t, 88, mesh.SurfaceArea(), 1e-10)
+	assertAlmostEqual(t, 0, mesh.Centroid().Sub(r3.Vector{X: 1, Y: 2, Z: 3}).Norm(), 1e-12)
+	assertInertia(t, [3][3]float64{{416, 0, 0}, {0, 320, 0}, {0, 0, 160}}, props.Inertia, 1e-9)
+}
+
+func TestMassPropertiesTetrahedron(t *testing.T) {
+	hull := convexHull([]r3.Vector{{X: 0}, {X: 1}, {Y: 1}, {Z: 1}})
+
+	props := hull.MassProperties(3)
+	assertAlmostEqual(t, 1.0/6, props.Volume, 1e-15)
+	assertAlmostEqual(t, 0.5, props.Mass, 1e-15)
+	assertAlmostEqual(t, 0, props.Centroid.Sub(r3.Vector{X: 0.25, Y: 0.25, Z: 0.25}).Norm(), 1e-15)
+
+	// Integral of x^2 around the centroid is 1/160, the one of x*y is -1/480
+	d, o := 3*2.0/160, 3*1.0/480
+	assertInertia(t, [3][3]float64{{d, o, o}, {o, d, o}, {o, o, d}}, props.Inertia, 1e-15)
+}
+
+func TestMassPropertiesDegenerate(t *testing.T) {
+	square := []r3.Vector{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0.5, Y: 0.5}}
+	hull := convexHull(square)
+
+	props := hull.MassProperties(1)
+	assertEqual(t, 0.0, props.Volume)
+	assertEqual(t, [3][3]float64{}, props.Inertia)
+	assertAlmostEqual(t, 0, props.Centroid.Sub(r3.Vector{X: 0.5, Y: 0.5}).Norm(), 1e-15)
+	assertAlmostEqual(t, 2, hull.SurfaceArea(), 1e-15)
+	assertAlmostEqual(t, 2, new(QuickHull).ConvexHullAsMesh(square, 0).SurfaceArea(), 1e-15)
+
+	segment := convexHull([]r3.Vector{{X: 0}, {X: 1}, {X: 0.5}})
+	assertEqual(t, r3.Vector{X: 0.5}, segment.Centroid())
+	assertEqual(t, 0.0, segment.SurfaceArea())
+}
@@ -27,6 +27,12 @@ func triangleNormal(a r3.Vector, b r3.Vector, c r3.Vector) r3.Vector {
 	}
 }
 
+func isFinite(v r3.Vector) bool {
+	return !math.IsNaN(v.X) && !math.IsInf(v.X, 0) &&
+		!math.IsNaN(v.Y) && !math.IsInf(v.Y, 0) &&
+		!math.IsNaN(v.Z) && !math.IsInf(v.Z, 0)
+}
+
 func signedDistanceToPlane(v r3.Vector, p plane) float64 {
 	return p.n.Dot(v) + p.d
 }
@@ -38,6 +44,20 @@ func squaredDistanceBetweenPointAndRay(p r3.Vector, r ray) float64 {
 
 }
 
+// Returns the point of the segment from a to b that is closest to p. If that is an endpoint, it is returned as is.
+func closestPointOnSegment(p, a, b r3.Vector) r3.Vector {
+	ab := b.Sub(a)
+	t := p.Sub(a).Dot(ab)
+	if t <= 0 {
+		return a
+	}
+	l := ab.Norm2()
+	if t >= l {
+		return b
+	}
+	return a.Add(ab.Mul(t / l))
+}
+
 // Find indices of extreme values (max x, min x, max y, min y, max z, min z) for the given point cloud
 func extremeValues(vertexData []r3.Vector) (extremeValueIndices [6]int) {
 	vd0 := vertexData[0]
@@ -96,3 +116,43 @@ func scale(vertexData []r3.Vector, extremeValueIndices [6]int) (s float64) {
 
 	return
 }
+
+// Returns a copy of the vertex data, translated and scaled so the bounding box is centered at the origin and its largest half extent is 1.
+// The returned unit is the length that was scaled to 1.
+func normalize(vertexData []r3.Vector, extremeValueIndices [6]int) (normalized []r3.Vector, unit float64) {
+	center, unit := normalization(boundingBox(vertexData, extremeValueIndices))
+
+	normalized = make([]r3.Vector, len(vertexData))
+	for i, v := range vertexData {
+		normalized[i] = v.Sub(center).Mul(1 / unit)
+	}
+
+	return normalized, unit
+}
+
+// Returns the corners of the axis aligned bounding box of the vertex data.
+func boundingBox(vertexData []r3.Vector, extremeValueIndices [6]int) (lo, hi r3.Vector) {
+	lo = r3.Vector{
+		X: vertexData[extremeValueIndices[1]].X,
+		Y: vertexData[extremeValueIndices[3]].Y,
+		Z: vertexData[extremeValueIndices[5]].Z,
+	}
+	hi = r3.Vector{
+		X: vertexData[extremeValueIndices[0]].X,
+		Y: vertexData[extremeValueIndices[2]].Y,
+		Z: vertexData[extremeValueIndices[4]].Z,
+	}
+	return lo, hi
+}
+
+// Returns the center and the largest half extent of a bounding box, i.e. the transform used by normalize.
+func normalization(lo, hi r3.Vector) (center r3.Vector, unit float64) {
+	center = lo.Add(hi).Mul(0.5)
+	halfExtent := hi.Sub(lo).Mul(0.5)
+	unit = math.Max(halfExtent.X, math.Max(halfExtent.Y, halfExtent.Z))
+	if unit == 0 {
+		// A single point
+		unit = 1
+	}
+	return center, unit
+}
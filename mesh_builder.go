@@ -76,6 +76,31 @@ func (mb *meshBuilder) halfEdgeIndicesOfFace(f meshBuilderFace) [3]int {
 	return [3]int{f.halfEdgeIndex, second, mb.halfEdges[second].Next}
 }
 
+// Replaces all point indices i (vertices, points on the positive side of Faces and interior points) by mapping[i].
+func (mb *meshBuilder) mapPointIndices(mapping []int) {
+	for i := range mb.halfEdges {
+		if he := &mb.halfEdges[i]; !he.isDisabled() {
+			he.EndVertex = mapping[he.EndVertex]
+		}
+	}
+
+	for i := range mb.faces {
+		f := &mb.faces[i]
+		if f.isDisabled() {
+			continue
+		}
+		for j, p := range f.pointsOnPositiveSide {
+			f.pointsOnPositiveSide[j] = mapping[p]
+		}
+		if len(f.pointsOnPositiveSide) > 0 {
+			f.mostDistantPoint = mapping[f.mostDistantPoint]
+		}
+		for j, p := range f.interiorPoints {
+			f.interiorPoints[j] = mapping[p]
+		}
+	}
+}
+
 // Create a mesh with initial tetrahedron ABCD. Dot product of AB with the normal of triangle ABC should be negative.
 func newMeshBuilder(a, b, c, d int) meshBuilder {
 	// Create halfedges
@@ -211,6 +236,21 @@ type meshBuilderFace struct {
 	inFaceStack                     bool
 	horizonEdgesOnCurrentIteration  byte // Bit for each half edge assigned to this Face, each being 0 or 1 depending on whether the edge belongs to horizon edge
 	pointsOnPositiveSide            []int
+	interiorPoints                  []int // Points inside the hull that are assigned to this Face, only used by DynamicHull
+}
+
+// Adds a point on the positive side of the Face, d is its distance to the plane of the Face.
+func (mbf *meshBuilderFace) addPoint(pointIndex int, d float64) {
+	/* TODO: optimize
+	if Face.pointsOnPositiveSide == nil {
+		f.m_pointsOnPositiveSide = std::move(getIndexVectorFromPool());
+	}
+	*/
+	mbf.pointsOnPositiveSide = append(mbf.pointsOnPositiveSide, pointIndex)
+	if d > mbf.mostDistantPointDist {
+		mbf.mostDistantPointDist = d
+		mbf.mostDistantPoint = pointIndex
+	}
 }
 
 func (mbf *meshBuilderFace) disable() {
@@ -0,0 +1,448 @@
+// Package nd computes convex hulls of point sets in any number of dimensions using the Quickhull algorithm.
+//
+// Facets are simplices, each facet knows its neighbors across its ridges (the faces of dimension d-2).
+// The three dimensional quickhull package is faster and handles degenerate input for d = 3, use this package for d >= 4.
+package nd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+// Hull is the convex hull of a d-dimensional point set.
+type Hull struct {
+	Dimension int     // Number of dimensions d of the points
+	Facets    []Facet // Facets of the hull
+	Vertices  []int   // Sorted indices of the input points that are vertices of the hull
+}
+
+// Facet is a (d-1)-dimensional simplex on the boundary of the hull.
+type Facet struct {
+	Vertices  []int     // Indices of the d input points spanning the facet
+	Neighbors []int     // Neighbors[i] is the index of the Facet sharing all Vertices except Vertices[i]
+	Normal    []float64 // Unit normal pointing out of the hull
+	Offset    float64   // Dot(Normal, p) + Offset is the signed distance of p to the hyperplane of the facet
+}
+
+// Distance returns the signed distance of p to the hyperplane of the facet, positive values are outside of the hull.
+func (f Facet) Distance(p []float64) float64 {
+	return dot(f.Normal, p) + f.Offset
+}
+
+// Internal facet, vertices and neighbors correspond to Facet.
+type facet struct {
+	vertices  []int
+	neighbors []int
+	normal    []float64
+	offset    float64
+
+	outside          []int // Points on the positive side that are assigned to this facet
+	farthest         int   // Point of outside which is the farthest away from the hyperplane
+	farthestDist     float64
+	visitedIteration int
+	visible          bool
+	disabled         bool
+}
+
+type builder struct {
+	points   [][]float64
+	dim      int
+	epsilon  float64
+	interior []float64 // A point strictly inside the hull, all normals point away from it
+	facets   []facet
+}
+
+// ConvexHull computes the convex hull of the given points, which must all have the same number of coordinates d >= 2.
+// Epsilon is relative to the largest absolute coordinate of the points like in quickhull, if <= 0 a default value will be used.
+// Returns an error of kind quickhull.KindDegenerateInput if the points don't span all d dimensions (within epsilon).
+func ConvexHull(points [][]float64, epsilon float64) (Hull, error) {
+	if len(points) == 0 {
+		return Hull{}, &quickhull.Error{Kind: quickhull.KindDegenerateInput, Msg: "point set is empty"}
+	}
+
+	d := len(points[0])
+	if d < 2 {
+		return Hull{}, &quickhull.Error{Kind: quickhull.KindInvalidArgument, Msg: fmt.Sprintf("points have %d dimensions, at least 2 are required", d)}
+	}
+
+	var scale float64
+	for i, p := range points {
+		if len(p) != d {
+			return Hull{}, &quickhull.Error{Kind: quickhull.KindInvalidArgument, Msg: fmt.Sprintf("point %d has %d dimensions, expected %d", i, len(p), d)}
+		}
+		for _, x := range p {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return Hull{}, &quickhull.Error{Kind: quickhull.KindNonFiniteInput, Msg: fmt.Sprintf("point %d is %v", i, p)}
+			}
+			scale = math.Max(scale, math.Abs(x))
+		}
+	}
+
+	if epsilon <= 0 {
+		epsilon = quickhull.DefaultEpsilon
+	}
+
+	b := builder{points: points, dim: d, epsilon: epsilon * scale}
+
+	simplex, err := b.initialSimplex()
+	if err != nil {
+		return Hull{}, err
+	}
+	b.createSimplex(simplex)
+
+	err = b.run()
+	if err != nil {
+		return Hull{}, err
+	}
+
+	return b.hull(), nil
+}
+
+// Selects d+1 points spanning a simplex of maximal size (greedily).
+func (b *builder) initialSimplex() ([]int, error) {
+	// Start with the two most distant points among the extreme points in each axis direction
+	var extremes []int
+	for k := 0; k < b.dim; k++ {
+		lo, hi := 0, 0
+		for i, p := range b.points {
+			if p[k] < b.points[lo][k] {
+				lo = i
+			}
+			if p[k] > b.points[hi][k] {
+				hi = i
+			}
+		}
+		extremes = append(extremes, lo, hi)
+	}
+
+	maxD := b.epsilon
+	p1, p2 := -1, -1
+	for i := range extremes {
+		for j := i + 1; j < len(extremes); j++ {
+			if dist := norm(sub(b.points[extremes[i]], b.points[extremes[j]])); dist > maxD {
+				maxD = dist
+				p1, p2 = extremes[i], extremes[j]
+			}
+		}
+	}
+	if p1 < 0 {
+		return nil, degenerate(0, b.dim)
+	}
+
+	// Add the point that is the farthest away from the affine subspace spanned by the points so far until we have d+1 points
+	simplex := []int{p1, p2}
+	origin := b.points[p1]
+	basis := [][]float64{normalized(sub(b.points[p2], origin))}
+	for len(simplex) <= b.dim {
+		maxD = b.epsilon
+		best := -1
+		var bestResidual []float64
+		for i, p := range b.points {
+			r := residual(sub(p, origin), basis)
+			if dist := norm(r); dist > maxD {
+				maxD = dist
+				best = i
+				bestResidual = r
+			}
+		}
+		if best < 0 {
+			return nil, degenerate(len(simplex)-1, b.dim)
+		}
+
+		simplex = append(simplex, best)
+		basis = append(basis, normalized(bestResidual))
+	}
+
+	return simplex, nil
+}
+
+func degenerate(spanned, dim int) error {
+	return &quickhull.Error{Kind: quickhull.KindDegenerateInput, Msg: fmt.Sprintf("points span only %d of %d dimensions", spanned, dim)}
+}
+
+// Creates the facets of the initial simplex and assigns all points to them.
+func (b *builder) createSimplex(simplex []int) {
+	b.interior = make([]float64, b.dim)
+	for _, i := range simplex {
+		for k, x := range b.points[i] {
+			b.interior[k] += x / float64(len(simplex))
+		}
+	}
+
+	// Facet i omits simplex[i], so it neighbors all other facets: the neighbor opposite of simplex[j] omits simplex[j]
+	for i := range simplex {
+		var f facet
+		for j, v := range simplex {
+			if j != i {
+				f.vertices = append(f.vertices, v)
+				f.neighbors = append(f.neighbors, j)
+			}
+		}
+		b.setHyperplane(&f)
+		b.facets = append(b.facets, f)
+	}
+
+	points := make([]int, len(b.points))
+	for i := range points {
+		points[i] = i
+	}
+	newFacets := make([]int, len(simplex))
+	for i := range newFacets {
+		newFacets[i] = i
+	}
+	b.assignPoints(points, newFacets)
+}
+
+// Computes the hyperplane through the vertices of the facet via cofactor expansion, oriented away from the interior point.
+func (b *builder) setHyperplane(f *facet) {
+	origin := b.points[f.vertices[0]]
+	edges := make([][]float64, 0, b.dim-1)
+	for _, v := range f.vertices[1:] {
+		edges = append(edges, sub(b.points[v], origin))
+	}
+
+	// The normal is the generalized cross product of the edges: n_k = (-1)^k * det(edges without column k)
+	n := make([]float64, b.dim)
+	minor := make([][]float64, b.dim-1)
+	for i := range minor {
+		minor[i] = make([]float64, b.dim-1)
+	}
+	for k := range n {
+		for i, e := range edges {
+			copy(minor[i], e[:k])
+			copy(minor[i][k:], e[k+1:])
+		}
+		n[k] = determinant(minor)
+		if k%2 == 1 {
+			n[k] = -n[k]
+		}
+	}
+
+	if l := norm(n); l > 0 {
+		for k := range n {
+			n[k] /= l
+		}
+	}
+	f.normal = n
+	f.offset = -dot(n, origin)
+
+	if dot(n, b.interior)+f.offset > 0 {
+		for k := range n {
+			n[k] = -n[k]
+		}
+		f.offset = -f.offset
+	}
+}
+
+// Assigns each point to the first of the given facets which has the point on its positive side (by more than epsilon).
+// Points that aren't outside any of the facets are discarded.
+func (b *builder) assignPoints(points []int, facets []int) {
+	for _, p := range points {
+		for _, fi := range facets {
+			f := &b.facets[fi]
+			if d := dot(f.normal, b.points[p]) + f.offset; d > b.epsilon {
+				f.outside = append(f.outside, p)
+				if len(f.outside) == 1 || d > f.farthestDist {
+					f.farthest = p
+					f.farthestDist = d
+				}
+				break
+			}
+		}
+	}
+}
+
+// Horizon ridge: the ridge of a visible facet opposite of vertices[index], shared with a facet that isn't visible.
+type horizonRidge struct {
+	facet int
+	index int
+}
+
+// Runs the main loop, adding the farthest outside point of a facet to the hull until no facet has outside points.
+func (b *builder) run() error {
+	var stack []int
+	for i := range b.facets {
+		if len(b.facets[i].outside) > 0 {
+			stack = append(stack, i)
+		}
+	}
+
+	var (
+		visible   []int
+		horizon   []horizonRidge
+		newFacets []int
+		points    []int
+	)
+	ridges := make(map[string]horizonRidge)
+	iteration := 0
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if b.facets[top].disabled || len(b.facets[top].outside) == 0 {
+			continue
+		}
+
+		iteration++
+		apex := b.facets[top].farthest
+		p := b.points[apex]
+
+		// Find the visible facets and the horizon by traversing the neighbors of visible facets
+		visible = visible[:0]
+		horizon = horizon[:0]
+		b.facets[top].visitedIteration = iteration
+		b.facets[top].visible = true
+		queue := []int{top}
+		for len(queue) > 0 {
+			fi := queue[0]
+			queue = queue[1:]
+			visible = append(visible, fi)
+
+			for i, ni := range b.facets[fi].neighbors {
+				n := &b.facets[ni]
+				if n.visitedIteration != iteration {
+					n.visitedIteration = iteration
+					n.visible = dot(n.normal, p)+n.offset > 0
+					if n.visible {
+						queue = append(queue, ni)
+					}
+				}
+				if !n.visible {
+					horizon = append(horizon, horizonRidge{facet: fi, index: i})
+				}
+			}
+		}
+
+		// Create a new facet for each horizon ridge, replacing the vertex opposite of the ridge by the apex
+		newFacets = newFacets[:0]
+		for _, h := range horizon {
+			old := &b.facets[h.facet]
+			f := facet{
+				vertices:  append([]int(nil), old.vertices...),
+				neighbors: make([]int, b.dim),
+			}
+			f.vertices[h.index] = apex
+			outer := old.neighbors[h.index]
+			f.neighbors[h.index] = outer
+			b.setHyperplane(&f)
+
+			fi := len(b.facets)
+			b.facets = append(b.facets, f)
+			newFacets = append(newFacets, fi)
+
+			// Point the facet behind the horizon to the new facet
+			on := b.facets[outer].neighbors
+			for k := range on {
+				if on[k] == h.facet {
+					on[k] = fi
+				}
+			}
+		}
+
+		// Connect the new facets with each other, they share the ridges containing the apex
+		for k := range ridges {
+			delete(ridges, k)
+		}
+		for _, fi := range newFacets {
+			f := &b.facets[fi]
+			for i, v := range f.vertices {
+				if v == apex {
+					continue
+				}
+				key := ridgeKey(f.vertices, i)
+				other, found := ridges[key]
+				if !found {
+					ridges[key] = horizonRidge{facet: fi, index: i}
+					continue
+				}
+				delete(ridges, key)
+				f.neighbors[i] = other.facet
+				b.facets[other.facet].neighbors[other.index] = fi
+			}
+		}
+		if len(ridges) > 0 {
+			return &quickhull.Error{Kind: quickhull.KindHorizonFailure, Msg: fmt.Sprintf("horizon of point %d isn't a closed ridge cycle", apex)}
+		}
+
+		// Redistribute the outside points of the visible facets among the new facets
+		points = points[:0]
+		for _, fi := range visible {
+			f := &b.facets[fi]
+			for _, q := range f.outside {
+				if q != apex {
+					points = append(points, q)
+				}
+			}
+			f.outside = nil
+			f.disabled = true
+		}
+		b.assignPoints(points, newFacets)
+
+		for _, fi := range newFacets {
+			if len(b.facets[fi].outside) > 0 {
+				stack = append(stack, fi)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns a key that is equal for ridges with the same vertices, the ridge consists of all vertices except vertices[skip].
+func ridgeKey(vertices []int, skip int) string {
+	ridge := make([]int, 0, len(vertices)-1)
+	for i, v := range vertices {
+		if i != skip {
+			ridge = append(ridge, v)
+		}
+	}
+	sort.Ints(ridge)
+
+	buf := make([]byte, 0, len(ridge)*binary.MaxVarintLen64)
+	var tmp [binary.MaxVarintLen64]byte
+	for _, v := range ridge {
+		n := binary.PutUvarint(tmp[:], uint64(v))
+		buf = append(buf, tmp[:n]...)
+	}
+	return string(buf)
+}
+
+// Returns the enabled facets with neighbor indices mapped accordingly.
+func (b *builder) hull() Hull {
+	hull := Hull{Dimension: b.dim}
+
+	mapping := make([]int, len(b.facets))
+	for i, f := range b.facets {
+		if f.disabled {
+			continue
+		}
+		mapping[i] = len(hull.Facets)
+		hull.Facets = append(hull.Facets, Facet{
+			Vertices:  f.vertices,
+			Neighbors: f.neighbors,
+			Normal:    f.normal,
+			Offset:    f.offset,
+		})
+	}
+
+	isVertex := make([]bool, len(b.points))
+	for _, f := range hull.Facets {
+		for i, n := range f.Neighbors {
+			f.Neighbors[i] = mapping[n]
+		}
+		for _, v := range f.Vertices {
+			isVertex[v] = true
+		}
+	}
+	for i, v := range isVertex {
+		if v {
+			hull.Vertices = append(hull.Vertices, i)
+		}
+	}
+
+	return hull
+}
@@ -0,0 +1,153 @@
+package nd
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/golang/geo/r3"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func assertErrorKind(t *testing.T, kind quickhull.ErrorKind, err error) {
+	t.Helper()
+
+	if e, ok := err.(*quickhull.Error); !ok || e.Kind != kind {
+		t.Errorf("expected error of kind %v, got %v", kind, err)
+	}
+}
+
+func randomPoints(r *rand.Rand, n, d int) [][]float64 {
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = make([]float64, d)
+		for k := range points[i] {
+			points[i][k] = r.NormFloat64()
+		}
+	}
+	return points
+}
+
+// Checks that the facets are consistently connected and that no point is outside of the hull.
+func assertValidHull(t *testing.T, points [][]float64, hull Hull) {
+	t.Helper()
+
+	for fi, f := range hull.Facets {
+		assertEqual(t, hull.Dimension, len(f.Vertices))
+		for i, ni := range f.Neighbors {
+			n := hull.Facets[ni]
+			shared := 0
+			for _, v := range n.Vertices {
+				for j, w := range f.Vertices {
+					if v == w && j != i {
+						shared++
+					}
+				}
+			}
+			if shared != hull.Dimension-1 {
+				t.Fatalf("facet %d and its neighbor %d share %d vertices", fi, ni, shared)
+			}
+			back := 0
+			for _, m := range n.Neighbors {
+				if m == fi {
+					back++
+				}
+			}
+			if back != 1 {
+				t.Fatalf("neighbor %d of facet %d doesn't link back", ni, fi)
+			}
+		}
+		for _, p := range points {
+			if d := f.Distance(p); d > 1e-9 {
+				t.Fatalf("point %v is outside of facet %d by %v", p, fi, d)
+			}
+		}
+	}
+}
+
+func TestConvexHullMatches3D(t *testing.T) {
+	points := randomPoints(rand.New(rand.NewSource(1)), 1000, 3)
+
+	hull, err := ConvexHull(points, 0)
+	assertEqual(t, nil, err)
+	assertValidHull(t, points, hull)
+
+	pointCloud := make([]r3.Vector, len(points))
+	for i, p := range points {
+		pointCloud[i] = r3.Vector{X: p[0], Y: p[1], Z: p[2]}
+	}
+	expected, err := new(quickhull.QuickHull).TryConvexHull(pointCloud, quickhull.Options{IndexMode: quickhull.OriginalIndices})
+	assertEqual(t, nil, err)
+
+	seen := make(map[int]bool)
+	var expectedVertices []int
+	for _, idx := range expected.Indices {
+		if !seen[idx] {
+			seen[idx] = true
+			expectedVertices = append(expectedVertices, idx)
+		}
+	}
+	sort.Ints(expectedVertices)
+
+	assertEqual(t, expectedVertices, hull.Vertices)
+	assertEqual(t, len(expected.Indices)/3, len(hull.Facets))
+}
+
+func TestConvexHullHigherDimensions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for d := 2; d <= 8; d++ {
+		// The number of facets grows exponentially with the dimension
+		n := 300
+		if d > 5 {
+			n = 60
+		}
+		points := randomPoints(r, n, d)
+
+		hull, err := ConvexHull(points, 0)
+		assertEqual(t, nil, err)
+		assertEqual(t, d, hull.Dimension)
+		assertValidHull(t, points, hull)
+	}
+}
+
+func TestConvexHullSphere4D(t *testing.T) {
+	// All points on the unit sphere are vertices
+	points := randomPoints(rand.New(rand.NewSource(1)), 200, 4)
+	for _, p := range points {
+		l := norm(p)
+		for k := range p {
+			p[k] /= l
+		}
+	}
+
+	hull, err := ConvexHull(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, len(points), len(hull.Vertices))
+	assertValidHull(t, points, hull)
+}
+
+func TestConvexHullErrors(t *testing.T) {
+	_, err := ConvexHull(nil, 0)
+	assertErrorKind(t, quickhull.KindDegenerateInput, err)
+
+	_, err = ConvexHull([][]float64{{0, 0, 0}, {1, 0}}, 0)
+	assertErrorKind(t, quickhull.KindInvalidArgument, err)
+
+	// All points lie on the hyperplane x3 = 1
+	points := randomPoints(rand.New(rand.NewSource(1)), 100, 4)
+	for _, p := range points {
+		p[3] = 1
+	}
+	_, err = ConvexHull(points, 0)
+	assertErrorKind(t, quickhull.KindDegenerateInput, err)
+}
@@ -0,0 +1,74 @@
+package nd
+
+import "math"
+
+func dot(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+func sub(a, b []float64) []float64 {
+	r := make([]float64, len(a))
+	for i := range a {
+		r[i] = a[i] - b[i]
+	}
+	return r
+}
+
+func norm(a []float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+func normalized(a []float64) []float64 {
+	l := norm(a)
+	r := make([]float64, len(a))
+	for i := range a {
+		r[i] = a[i] / l
+	}
+	return r
+}
+
+// Returns the component of v that is orthogonal to all vectors of the orthonormal basis.
+func residual(v []float64, basis [][]float64) []float64 {
+	r := append([]float64(nil), v...)
+	for _, e := range basis {
+		d := dot(r, e)
+		for i := range r {
+			r[i] -= d * e[i]
+		}
+	}
+	return r
+}
+
+// Returns the determinant of the square matrix m using Gaussian elimination with partial pivoting.
+// The matrix is modified.
+func determinant(m [][]float64) float64 {
+	det := 1.0
+	n := len(m)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if m[pivot][col] == 0 {
+			return 0
+		}
+		if pivot != col {
+			m[pivot], m[col] = m[col], m[pivot]
+			det = -det
+		}
+		det *= m[col][col]
+		for row := col + 1; row < n; row++ {
+			f := m[row][col] / m[col][col]
+			for k := col; k < n; k++ {
+				m[row][k] -= f * m[col][k]
+			}
+		}
+	}
+	return det
+}
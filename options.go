@@ -0,0 +1,103 @@
+package quickhull
+
+import "time"
+
+// Winding defines the vertex order of the triangles of a ConvexHull.
+type Winding int
+
+const (
+	// CounterClockwise orders the vertices of each triangle like ConvexHull does with ccw = true.
+	// The right-hand normals of the triangles point into the hull.
+	CounterClockwise Winding = iota
+	// Clockwise orders the vertices of each triangle like ConvexHull does with ccw = false.
+	// The right-hand normals of the triangles point out of the hull.
+	Clockwise
+)
+
+// IndexMode defines what the vertex indices of a hull refer to.
+type IndexMode int
+
+const (
+	// CompactIndices makes the hull contain only its own vertices, indices refer to those.
+	CompactIndices IndexMode = iota
+	// OriginalIndices makes the hull reuse the input point cloud as vertices, indices refer to the point cloud.
+	OriginalIndices
+)
+
+// EpsilonMode defines how Options.Epsilon is interpreted.
+type EpsilonMode int
+
+const (
+	// RelativeEpsilon scales the epsilon by the largest absolute coordinate of the point cloud.
+	RelativeEpsilon EpsilonMode = iota
+	// AbsoluteEpsilon uses the epsilon as is.
+	AbsoluteEpsilon
+)
+
+// Options configure the hull construction.
+// The zero value is equivalent to calling ConvexHull(pointCloud, true, false, 0),
+// except that the hulls of points and segments have no triangles (see ConvexHull.Dimension).
+type Options struct {
+	Winding     Winding     // Vertex order of the output triangles, ignored for HalfEdgeMesh output
+	IndexMode   IndexMode   // What vertex indices refer to
+	Epsilon     float64     // Tolerance for numerical comparisons, if <= 0 a default value will be used
+	EpsilonMode EpsilonMode // How Epsilon is interpreted
+
+	// If true, the point cloud is centered and scaled into the unit box internally before building the hull.
+	// This makes a RelativeEpsilon relative to the extent of the point cloud instead of its largest absolute coordinate,
+	// so translated copies of a point cloud result in the same hull. An AbsoluteEpsilon is still in the units of the input.
+	// The output refers to the original points.
+	Normalize bool
+
+	// Receives warnings emitted during the construction (e.g. failed horizon edges), if nil warnings are discarded.
+	// See also the Diagnostics returned with the hull.
+	Logger Logger
+
+	// If true, the output contains the unit normal (pointing out of the hull) of each triangle / face.
+	ComputeNormals bool
+
+	// If true, visibility tests use exact (adaptive precision) predicates instead of plain floating point arithmetic.
+	// This guarantees a convex hull without failed horizon edges, even for nearly coplanar input, at a small performance cost.
+	Robust bool
+
+	// If true and the horizon edge can't be solved for some points, the input is perturbed by a tiny random amount (relative to
+	// the scale of the point cloud, growing with each attempt) and the hull is built again, similar to qhull's 'QJ' option.
+	// The output refers to the original (unperturbed) points. Diagnostics report the joggle magnitude used.
+	Joggle bool
+	// Seed for the random perturbations of Joggle, the result is deterministic for a given seed.
+	JoggleSeed int64
+
+	// If true, points strictly inside the polytope spanned by the extreme points in the axis and diagonal directions are
+	// discarded before running the algorithm (Akl–Toussaint heuristic). Speeds up the construction for dense point clouds.
+	CullInterior bool
+
+	// Number of goroutines used to assign points to faces, values <= 1 disable parallelism.
+	// Only worth it for large point clouds, the result is identical to the serial algorithm. runtime.NumCPU() is a good choice.
+	Workers int
+
+	// Budgets, zero means unlimited. When a budget is exhausted the partial hull is returned together with an error.
+	MaxIterations int           // Maximum number of iterations of the main loop (roughly the number of points added to the hull)
+	MaxFaces      int           // Construction stops once the hull has at least this many faces
+	Timeout       time.Duration // Construction stops after this duration, in addition to the deadline of the context (if any)
+
+	legacy bool // Whether points and segments are output as degenerate tetrahedron like ConvexHull and ConvexHullAsMesh always did
+}
+
+// Returns the Options equivalent to the positional arguments of ConvexHull.
+func legacyOptions(ccw bool, useOriginalIndices bool, epsilon float64) Options {
+	opts := Options{Epsilon: epsilon, Logger: stdLogger{}, legacy: true}
+	if !ccw {
+		opts.Winding = Clockwise
+	}
+	if useOriginalIndices {
+		opts.IndexMode = OriginalIndices
+	}
+	return opts
+}
+
+func (opts Options) epsilon() float64 {
+	if opts.Epsilon <= 0 {
+		return DefaultEpsilon
+	}
+	return opts.Epsilon
+}
@@ -0,0 +1,110 @@
+package quickhull
+
+import (
+	"context"
+	"sync"
+)
+
+// Below this number of points, spreading the partitioning over multiple goroutines isn't worth it.
+const parallelPartitionThreshold = 4096
+
+// Result of testing a point against a list of Faces.
+type pointAssignment struct {
+	face int     // Index of the first Face (in the list) which has the point on its positive side, -1 if none
+	dist float64 // Signed distance of the point to the plane of that Face
+}
+
+// Associates each point with the first of the given Faces which has the point on its positive side.
+// The distance computations are spread over qh.workers goroutines. The points are added to the Faces in order afterwards,
+// so the resulting mesh doesn't depend on the number of workers.
+// The returned assignments are only valid until the next call.
+// If ctx is done before all distances are computed, no point is added and ctx.Err() is returned.
+func (qh *QuickHull) assignPointsToFaces(ctx context.Context, points []int, faces []*meshBuilderFace) ([]pointAssignment, error) {
+	if cap(qh.pointAssignments) < len(points) {
+		qh.pointAssignments = make([]pointAssignment, len(points))
+	}
+	assignments := qh.pointAssignments[:len(points)]
+
+	var (
+		mu  sync.Mutex
+		err error
+	)
+	assign := func(from, to int) {
+		for k := from; k < to; k++ {
+			if ctxErr := checkContext(ctx, k); ctxErr != nil {
+				mu.Lock()
+				err = ctxErr
+				mu.Unlock()
+				return
+			}
+			assignments[k] = pointAssignment{face: -1}
+			for j, f := range faces {
+				if d, outside := qh.distanceIfOnPositiveSide(f, points[k]); outside {
+					assignments[k] = pointAssignment{face: j, dist: d}
+					break
+				}
+			}
+		}
+	}
+
+	workers := qh.workers
+	if len(points) < parallelPartitionThreshold {
+		workers = 1
+	}
+	parallelFor(len(points), workers, assign)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, a := range assignments {
+		if a.face >= 0 {
+			faces[a.face].addPoint(points[k], a.dist)
+		}
+	}
+
+	return assignments, nil
+}
+
+// Returns the signed distance of the point to the plane of the Face and whether the point resides on the positive side of the plane (by more than epsilon).
+func (qh *QuickHull) distanceIfOnPositiveSide(face *meshBuilderFace, pointIndex int) (float64, bool) {
+	d := signedDistanceToPlane(qh.vertexData[pointIndex], face.plane)
+	outside := d > 0 && d*d > qh.epsilonSquared*face.plane.sqrNLength
+	if outside && qh.robust {
+		// Make sure the point is visible from the Face when checked exactly, otherwise it may not be possible to solve the horizon edge
+		outside = qh.isOnPositiveSide(face, pointIndex)
+	}
+	return d, outside
+}
+
+// Reports whether the point is on the positive side of the plane of the Face.
+// With robust predicates enabled the result is exact, otherwise it's subject to rounding errors.
+func (qh *QuickHull) isOnPositiveSide(face *meshBuilderFace, pointIndex int) bool {
+	if qh.robust {
+		v := qh.mesh.vertexIndicesOfFace(*face)
+		return orientation(qh.vertexData[v[0]], qh.vertexData[v[1]], qh.vertexData[v[2]], qh.vertexData[pointIndex]) > 0
+	}
+	return signedDistanceToPlane(qh.vertexData[pointIndex], face.plane) > 0
+}
+
+// Calls f for consecutive chunks of [0, n), using one goroutine per chunk if workers > 1.
+func parallelFor(n int, workers int, f func(from, to int)) {
+	if workers <= 1 {
+		f(0, n)
+		return
+	}
+
+	var wg sync.WaitGroup
+	chunkSize := (n + workers - 1) / workers
+	for from := 0; from < n; from += chunkSize {
+		to := from + chunkSize
+		if to > n {
+			to = n
+		}
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			f(from, to)
+		}(from, to)
+	}
+	wg.Wait()
+}
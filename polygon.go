@@ -0,0 +1,76 @@
+package quickhull
+
+import (
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+)
+
+// Polygon is the convex hull of a planar point cloud.
+type Polygon struct {
+	Indices  []int       // Vertex indices of the ordered boundary, same as ConvexHull.Outline
+	Vertices []r3.Vector // Ordered boundary
+	Normal   r3.Vector   // Unit normal of the plane, its largest (absolute) component is positive
+	Offset   float64     // Normal.Dot(p) for points p on the plane
+	U, V     r3.Vector   // Orthonormal basis of the plane with U.Cross(V) == Normal
+	Points   []r2.Point  // Ordered boundary projected onto the plane: (Vertices[i].Dot(U), Vertices[i].Dot(V))
+}
+
+// Returns the polygon with the given boundary along with the reordered boundary.
+// The boundary is counter-clockwise when viewed from the side Normal points to if ccw is true and clockwise otherwise.
+func newPolygon(pointCloud []r3.Vector, outline []int, ccw bool) ([]int, *Polygon) {
+	// Normal of the loop, pointing to the side from where it appears counter-clockwise
+	var n r3.Vector
+	origin := pointCloud[outline[0]]
+	for i := 1; i+1 < len(outline); i++ {
+		n = n.Add(triangleNormal(pointCloud[outline[i]], pointCloud[outline[i+1]], origin))
+	}
+
+	normal := n
+	switch normal.LargestComponent() {
+	case r3.XAxis:
+		if normal.X < 0 {
+			normal = normal.Mul(-1)
+		}
+	case r3.YAxis:
+		if normal.Y < 0 {
+			normal = normal.Mul(-1)
+		}
+	default:
+		if normal.Z < 0 {
+			normal = normal.Mul(-1)
+		}
+	}
+	normal = normal.Normalize()
+
+	ordered := append([]int(nil), outline...)
+	if (n.Dot(normal) > 0) != ccw {
+		for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		}
+	}
+
+	// U is the axis that is most parallel to the plane, projected onto the plane
+	axis := r3.Vector{X: 1}
+	if a := normal.Abs(); a.Y < a.X && a.Y <= a.Z {
+		axis = r3.Vector{Y: 1}
+	} else if a.Z < a.X && a.Z < a.Y {
+		axis = r3.Vector{Z: 1}
+	}
+	u := axis.Sub(normal.Mul(axis.Dot(normal))).Normalize()
+
+	poly := &Polygon{
+		Indices: ordered,
+		Normal:  normal,
+		U:       u,
+		V:       normal.Cross(u),
+	}
+	for _, idx := range ordered {
+		v := pointCloud[idx]
+		poly.Vertices = append(poly.Vertices, v)
+		poly.Offset += normal.Dot(v)
+		poly.Points = append(poly.Points, r2.Point{X: v.Dot(poly.U), Y: v.Dot(poly.V)})
+	}
+	poly.Offset /= float64(len(ordered))
+
+	return ordered, poly
+}
@@ -0,0 +1,128 @@
+// Package power computes power diagrams (weighted Voronoi diagrams) and regular triangulations (weighted Delaunay
+// triangulations) of two dimensional point sets.
+//
+// The sites are lifted to (x, y, x² + y² - w) and hulled with quickhull. The lower faces of the hull form the regular
+// triangulation, their power centers are the vertices of the power diagram. Sites whose lifted point is above the
+// lower hull are hidden: their weight is too small to claim any region.
+package power
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+	"github.com/markus-wa/quickhull-go/v2/internal/paraboloid"
+)
+
+// Diagram is a power diagram clipped to a bounding box, along with the regular triangulation.
+type Diagram struct {
+	// Counter-clockwise triangles of the regular triangulation, referring to the indices of the sites.
+	// Very flat triangles along the convex hull of the sites, whose power center is far outside of the bounds, may be missing.
+	Triangles [][3]int
+	// Cells[i] is the counter-clockwise polygon of the region where site i has the smallest power distance |p - site|² - weight.
+	// Empty if site i is hidden or its region doesn't intersect the bounds.
+	Cells [][]r2.Point
+	// Neighbors[i] are the sorted indices of the sites whose (unclipped) cells share an edge with the cell of site i.
+	Neighbors [][]int
+	// Sorted indices of the hidden sites, which have no cell. Sites that (nearly) coincide with another site are hidden as well.
+	Hidden []int
+}
+
+// Compute computes the power diagram of the sites with the given weights (e.g. squared radii), with cells clipped to bounds.
+// If epsilon is <= 0 a default value will be used, it's relative to the extent of the sites and bounds.
+// Returns an error of kind quickhull.KindInvalidArgument if bounds is empty or the number of weights doesn't match.
+func Compute(sites []r2.Point, weights []float64, bounds r2.Rect, epsilon float64) (Diagram, error) {
+	if bounds.IsEmpty() {
+		return Diagram{}, &quickhull.Error{Kind: quickhull.KindInvalidArgument, Msg: fmt.Sprintf("bounds %v are empty", bounds)}
+	}
+	if len(weights) != len(sites) {
+		return Diagram{}, &quickhull.Error{Kind: quickhull.KindInvalidArgument, Msg: fmt.Sprintf("got %d weights for %d sites", len(weights), len(sites))}
+	}
+	if len(sites) == 0 {
+		return Diagram{}, nil
+	}
+	if epsilon <= 0 {
+		epsilon = quickhull.DefaultEpsilon
+	}
+
+	minWeight := math.Inf(1)
+	for i, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return Diagram{}, &quickhull.Error{Kind: quickhull.KindNonFiniteInput, Msg: fmt.Sprintf("weight %d is %v", i, w)}
+		}
+		minWeight = math.Min(minWeight, w)
+	}
+
+	// Surround everything with ghost sites, so the cells of all real sites are bounded.
+	// Giving them the smallest weight keeps them from claiming any region inside the bounds.
+	n := len(sites)
+	points := append(append([]r2.Point(nil), sites...), paraboloid.Ghosts(sites, bounds)...)
+	allWeights := append([]float64(nil), weights...)
+	for len(allWeights) < len(points) {
+		allWeights = append(allWeights, minWeight)
+	}
+
+	t, err := paraboloid.NewTransform(points)
+	if err != nil {
+		return Diagram{}, err
+	}
+
+	mesh, err := new(quickhull.QuickHull).TryConvexHullAsMesh(t.Lift(points, allWeights), quickhull.Options{
+		Epsilon:        epsilon,
+		IndexMode:      quickhull.OriginalIndices,
+		ComputeNormals: true,
+		Robust:         true,
+	})
+	if err != nil {
+		return Diagram{}, err
+	}
+
+	diagram := Diagram{
+		Neighbors: make([][]int, n),
+	}
+
+	for i, f := range mesh.Faces {
+		a := mesh.HalfEdges[f.HalfEdge]
+		b := mesh.HalfEdges[a.Next]
+		c := mesh.HalfEdges[b.Next]
+		if mesh.Normals[i].Z < 0 && a.EndVertex < n && b.EndVertex < n && c.EndVertex < n {
+			// The right-hand normal of lower faces points down, so they are clockwise when viewed from above
+			diagram.Triangles = append(diagram.Triangles, [3]int{a.EndVertex, c.EndVertex, b.EndVertex})
+		}
+	}
+
+	isVertex := make([]bool, n)
+	for _, he := range mesh.HalfEdges {
+		from := mesh.HalfEdges[he.Opp].EndVertex
+		if he.EndVertex < n {
+			isVertex[he.EndVertex] = true
+			if from < n {
+				diagram.Neighbors[from] = append(diagram.Neighbors[from], he.EndVertex)
+			}
+		}
+	}
+	for i := range diagram.Neighbors {
+		sort.Ints(diagram.Neighbors[i])
+		if !isVertex[i] {
+			diagram.Hidden = append(diagram.Hidden, i)
+		}
+	}
+
+	powerCenter := func(a, b, c int) r2.Point {
+		return PowerCenter(points[a], points[b], points[c], allWeights[a], allWeights[b], allWeights[c])
+	}
+	diagram.Cells = paraboloid.Cells(mesh, n, powerCenter, bounds)
+
+	return diagram, nil
+}
+
+// PowerCenter returns the point with the same power distance to a, b and c, which is the vertex of their power diagram.
+func PowerCenter(a, b, c r2.Point, wa, wb, wc float64) r2.Point {
+	b, c = b.Sub(a), c.Sub(a)
+	d := 2 * b.Cross(c)
+	bb, cc := b.Dot(b)+wa-wb, c.Dot(c)+wa-wc
+	return a.Add(r2.Point{X: (c.Y*bb - b.Y*cc) / d, Y: (b.X*cc - c.X*bb) / d})
+}
@@ -0,0 +1,167 @@
+package power
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+	"github.com/markus-wa/quickhull-go/v2/voronoi"
+)
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func assertAlmostEqual(t *testing.T, expected, actual float64) {
+	t.Helper()
+
+	if math.Abs(expected-actual) > 1e-9 {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func area(polygon []r2.Point) float64 {
+	var a float64
+	for i, p := range polygon {
+		a += p.Cross(polygon[(i+1)%len(polygon)])
+	}
+	return a / 2
+}
+
+func contains(polygon []r2.Point, p r2.Point) bool {
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if b.Sub(a).Cross(p.Sub(a)) < -1e-9 {
+			return false
+		}
+	}
+	return len(polygon) > 0
+}
+
+func unitSquare() r2.Rect {
+	return r2.Rect{X: r1.Interval{Lo: 0, Hi: 1}, Y: r1.Interval{Lo: 0, Hi: 1}}
+}
+
+func powerDistance(p, site r2.Point, weight float64) float64 {
+	d := p.Sub(site)
+	return d.Dot(d) - weight
+}
+
+func TestComputeTwoSites(t *testing.T) {
+	diagram, err := Compute([]r2.Point{{X: 0.25, Y: 0.5}, {X: 0.75, Y: 0.5}}, []float64{0.1, 0}, unitSquare(), 0)
+	assertEqual(t, nil, err)
+	assertAlmostEqual(t, 0.6, area(diagram.Cells[0]))
+	assertAlmostEqual(t, 0.4, area(diagram.Cells[1]))
+	assertEqual(t, [][]int{{1}, {0}}, diagram.Neighbors)
+	assertEqual(t, []int(nil), diagram.Hidden)
+}
+
+func TestComputeHidden(t *testing.T) {
+	sites := []r2.Point{{X: 0.25, Y: 0.5}, {X: 0.5, Y: 0.5}, {X: 0.75, Y: 0.5}}
+
+	diagram, err := Compute(sites, []float64{0.2, 0, 0.2}, unitSquare(), 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, []int{1}, diagram.Hidden)
+	assertEqual(t, 0, len(diagram.Cells[1]))
+	assertEqual(t, [][]int{{2}, nil, {0}}, diagram.Neighbors)
+	assertAlmostEqual(t, 1, area(diagram.Cells[0])+area(diagram.Cells[2]))
+}
+
+func TestComputeEqualWeightsMatchesVoronoi(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sites := make([]r2.Point, 100)
+	weights := make([]float64, len(sites))
+	for i := range sites {
+		sites[i] = r2.Point{X: r.Float64(), Y: r.Float64()}
+		weights[i] = 0.5
+	}
+
+	diagram, err := Compute(sites, weights, unitSquare(), 0)
+	assertEqual(t, nil, err)
+	expected, err := voronoi.Compute(sites, unitSquare(), 0)
+	assertEqual(t, nil, err)
+
+	for i := range sites {
+		assertAlmostEqual(t, area(expected.Cells[i]), area(diagram.Cells[i]))
+	}
+}
+
+func TestComputeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sites := make([]r2.Point, 200)
+	weights := make([]float64, len(sites))
+	for i := range sites {
+		sites[i] = r2.Point{X: r.Float64(), Y: r.Float64()}
+		weights[i] = r.Float64() * 0.01
+	}
+
+	diagram, err := Compute(sites, weights, unitSquare(), 0)
+	assertEqual(t, nil, err)
+
+	var total float64
+	for _, cell := range diagram.Cells {
+		total += area(cell)
+	}
+	assertAlmostEqual(t, 1, total)
+
+	// Every point is in the cell of the site with the smallest power distance
+	for k := 0; k < 1000; k++ {
+		p := r2.Point{X: r.Float64(), Y: r.Float64()}
+		nearest := 0
+		for i, s := range sites {
+			if powerDistance(p, s, weights[i]) < powerDistance(p, sites[nearest], weights[nearest]) {
+				nearest = i
+			}
+		}
+		if !contains(diagram.Cells[nearest], p) {
+			t.Fatalf("point %v isn't in the cell of site %d", p, nearest)
+		}
+	}
+
+	// No site is closer to the power center of a triangle than the corners of the triangle
+	for _, tri := range diagram.Triangles {
+		a, b, c := sites[tri[0]], sites[tri[1]], sites[tri[2]]
+		if b.Sub(a).Cross(c.Sub(a)) <= 0 {
+			t.Fatalf("triangle %v isn't counter-clockwise", tri)
+		}
+		center := PowerCenter(a, b, c, weights[tri[0]], weights[tri[1]], weights[tri[2]])
+		d := powerDistance(center, a, weights[tri[0]])
+		for i, s := range sites {
+			if powerDistance(center, s, weights[i]) < d-1e-9 {
+				t.Fatalf("site %d is closer to the power center of %v", i, tri)
+			}
+		}
+	}
+
+	for i, neighbors := range diagram.Neighbors {
+		for _, j := range neighbors {
+			found := false
+			for _, k := range diagram.Neighbors[j] {
+				found = found || k == i
+			}
+			if !found {
+				t.Fatalf("site %d is a neighbor of %d but not vice versa", j, i)
+			}
+		}
+	}
+	for _, i := range diagram.Hidden {
+		assertEqual(t, 0, len(diagram.Cells[i]))
+	}
+}
+
+func TestComputeErrors(t *testing.T) {
+	_, err := Compute([]r2.Point{{X: 0.5, Y: 0.5}}, nil, unitSquare(), 0)
+	assertEqual(t, quickhull.KindInvalidArgument, err.(*quickhull.Error).Kind)
+
+	_, err = Compute([]r2.Point{{X: 0.5, Y: 0.5}}, []float64{math.NaN()}, unitSquare(), 0)
+	assertEqual(t, quickhull.KindNonFiniteInput, err.(*quickhull.Error).Kind)
+}
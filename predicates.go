@@ -0,0 +1,173 @@
+package quickhull
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/golang/geo/r3"
+)
+
+// Error bound of the floating point evaluation of orientation, see Shewchuk's "Adaptive Precision Floating-Point Arithmetic and Fast Robust Geometric Predicates".
+var orientationErrBound = (7 + 56*epsilon64) * epsilon64
+
+// Machine epsilon for float64 (half an ulp of 1).
+const epsilon64 = 1.0 / (1 << 53)
+
+// Returns the sign of the signed distance of p to the plane through a, b and c with normal triangleNormal(a, b, c), i.e.
+// 1 if p is on the positive side of the plane, -1 if it's on the negative side and 0 if it's on the plane.
+// The result is exact: the determinant is evaluated in floating point first and only if its sign can't be determined
+// due to rounding errors, it's computed again using exact rational arithmetic.
+func orientation(a, b, c, p r3.Vector) int {
+	ux, uy, uz := a.X-c.X, a.Y-c.Y, a.Z-c.Z
+	vx, vy, vz := b.X-c.X, b.Y-c.Y, b.Z-c.Z
+	wx, wy, wz := p.X-c.X, p.Y-c.Y, p.Z-c.Z
+
+	vywz, vzwy := vy*wz, vz*wy
+	vzwx, vxwz := vz*wx, vx*wz
+	vxwy, vywx := vx*wy, vy*wx
+
+	det := ux*(vywz-vzwy) + uy*(vzwx-vxwz) + uz*(vxwy-vywx)
+
+	permanent := (math.Abs(vywz)+math.Abs(vzwy))*math.Abs(ux) +
+		(math.Abs(vzwx)+math.Abs(vxwz))*math.Abs(uy) +
+		(math.Abs(vxwy)+math.Abs(vywx))*math.Abs(uz)
+	errBound := orientationErrBound * permanent
+
+	if det > errBound {
+		return 1
+	}
+	if -det > errBound {
+		return -1
+	}
+
+	return exactOrientation(a, b, c, p)
+}
+
+func exactOrientation(a, b, c, p r3.Vector) int {
+	rat := func(f float64) *big.Rat {
+		return new(big.Rat).SetFloat64(f)
+	}
+	sub := func(x, y float64) *big.Rat {
+		return new(big.Rat).Sub(rat(x), rat(y))
+	}
+	mul := func(x, y *big.Rat) *big.Rat {
+		return new(big.Rat).Mul(x, y)
+	}
+	// x*y - z*w
+	cross := func(x, y, z, w *big.Rat) *big.Rat {
+		return new(big.Rat).Sub(mul(x, y), mul(z, w))
+	}
+
+	ux, uy, uz := sub(a.X, c.X), sub(a.Y, c.Y), sub(a.Z, c.Z)
+	vx, vy, vz := sub(b.X, c.X), sub(b.Y, c.Y), sub(b.Z, c.Z)
+	wx, wy, wz := sub(p.X, c.X), sub(p.Y, c.Y), sub(p.Z, c.Z)
+
+	det := mul(ux, cross(vy, wz, vz, wy))
+	det.Add(det, mul(uy, cross(vz, wx, vx, wz)))
+	det.Add(det, mul(uz, cross(vx, wy, vy, wx)))
+
+	return det.Sign()
+}
+
+// Error bound of the floating point evaluation of inSphere.
+var inSphereErrBound = (16 + 224*epsilon64) * epsilon64
+
+// Returns 1 if p is inside the sphere through a, b, c and d, -1 if it's outside and 0 if it's on the sphere.
+// The points a, b, c and d must be positively oriented, i.e. orientation(a, b, c, d) > 0. The result is exact.
+func inSphere(a, b, c, d, p r3.Vector) int {
+	adx, ady, adz := a.X-p.X, a.Y-p.Y, a.Z-p.Z
+	bdx, bdy, bdz := b.X-p.X, b.Y-p.Y, b.Z-p.Z
+	cdx, cdy, cdz := c.X-p.X, c.Y-p.Y, c.Z-p.Z
+	ddx, ddy, ddz := d.X-p.X, d.Y-p.Y, d.Z-p.Z
+
+	// 2x2 minors of the x and y columns and their permanents
+	minor := func(x1, y1, x2, y2 float64) (float64, float64) {
+		return x1*y2 - x2*y1, math.Abs(x1*y2) + math.Abs(x2*y1)
+	}
+	ab, abP := minor(adx, ady, bdx, bdy)
+	bc, bcP := minor(bdx, bdy, cdx, cdy)
+	cd, cdP := minor(cdx, cdy, ddx, ddy)
+	da, daP := minor(ddx, ddy, adx, ady)
+	ac, acP := minor(adx, ady, cdx, cdy)
+	bd, bdP := minor(bdx, bdy, ddx, ddy)
+
+	abc := adz*bc - bdz*ac + cdz*ab
+	bcd := bdz*cd - cdz*bd + ddz*bc
+	cda := cdz*da + ddz*ac + adz*cd
+	dab := ddz*ab + adz*bd + bdz*da
+	abcP := math.Abs(adz)*bcP + math.Abs(bdz)*acP + math.Abs(cdz)*abP
+	bcdP := math.Abs(bdz)*cdP + math.Abs(cdz)*bdP + math.Abs(ddz)*bcP
+	cdaP := math.Abs(cdz)*daP + math.Abs(ddz)*acP + math.Abs(adz)*cdP
+	dabP := math.Abs(ddz)*abP + math.Abs(adz)*bdP + math.Abs(bdz)*daP
+
+	alift := adx*adx + ady*ady + adz*adz
+	blift := bdx*bdx + bdy*bdy + bdz*bdz
+	clift := cdx*cdx + cdy*cdy + cdz*cdz
+	dlift := ddx*ddx + ddy*ddy + ddz*ddz
+
+	det := (dlift*abc - clift*dab) + (blift*cda - alift*bcd)
+	permanent := dlift*abcP + clift*dabP + blift*cdaP + alift*bcdP
+	errBound := inSphereErrBound * permanent
+
+	// The determinant is positive for points inside the sphere if a, b, c and d are negatively oriented in Shewchuk's convention,
+	// which is the positive orientation of orientation()
+	if det > errBound {
+		return -1
+	}
+	if -det > errBound {
+		return 1
+	}
+
+	return -exactInSphere(a, b, c, d, p)
+}
+
+func exactInSphere(a, b, c, d, p r3.Vector) int {
+	sub := func(x, y float64) *big.Rat {
+		return new(big.Rat).Sub(new(big.Rat).SetFloat64(x), new(big.Rat).SetFloat64(y))
+	}
+	mul := func(x, y *big.Rat) *big.Rat {
+		return new(big.Rat).Mul(x, y)
+	}
+	add := func(xs ...*big.Rat) *big.Rat {
+		s := new(big.Rat)
+		for _, x := range xs {
+			s.Add(s, x)
+		}
+		return s
+	}
+	neg := func(x *big.Rat) *big.Rat {
+		return new(big.Rat).Neg(x)
+	}
+
+	adx, ady, adz := sub(a.X, p.X), sub(a.Y, p.Y), sub(a.Z, p.Z)
+	bdx, bdy, bdz := sub(b.X, p.X), sub(b.Y, p.Y), sub(b.Z, p.Z)
+	cdx, cdy, cdz := sub(c.X, p.X), sub(c.Y, p.Y), sub(c.Z, p.Z)
+	ddx, ddy, ddz := sub(d.X, p.X), sub(d.Y, p.Y), sub(d.Z, p.Z)
+
+	minor := func(x1, y1, x2, y2 *big.Rat) *big.Rat {
+		return add(mul(x1, y2), neg(mul(x2, y1)))
+	}
+	ab := minor(adx, ady, bdx, bdy)
+	bc := minor(bdx, bdy, cdx, cdy)
+	cd := minor(cdx, cdy, ddx, ddy)
+	da := minor(ddx, ddy, adx, ady)
+	ac := minor(adx, ady, cdx, cdy)
+	bd := minor(bdx, bdy, ddx, ddy)
+
+	abc := add(mul(adz, bc), neg(mul(bdz, ac)), mul(cdz, ab))
+	bcd := add(mul(bdz, cd), neg(mul(cdz, bd)), mul(ddz, bc))
+	cda := add(mul(cdz, da), mul(ddz, ac), mul(adz, cd))
+	dab := add(mul(ddz, ab), mul(adz, bd), mul(bdz, da))
+
+	lift := func(x, y, z *big.Rat) *big.Rat {
+		return add(mul(x, x), mul(y, y), mul(z, z))
+	}
+	alift := lift(adx, ady, adz)
+	blift := lift(bdx, bdy, bdz)
+	clift := lift(cdx, cdy, cdz)
+	dlift := lift(ddx, ddy, ddz)
+
+	det := add(mul(dlift, abc), neg(mul(clift, dab)), mul(blift, cda), neg(mul(alift, bcd)))
+
+	return det.Sign()
+}
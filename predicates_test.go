@@ -0,0 +1,72 @@
+package quickhull
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestOrientation(t *testing.T) {
+	a := r3.Vector{X: 0, Y: 0, Z: 0}
+	b := r3.Vector{X: 1, Y: 0, Z: 0}
+	c := r3.Vector{X: 0, Y: 1, Z: 0}
+
+	// triangleNormal(a, b, c) points towards +Z
+	assertEqual(t, 1, orientation(a, b, c, r3.Vector{X: 0.2, Y: 0.2, Z: 1}))
+	assertEqual(t, -1, orientation(a, b, c, r3.Vector{X: 0.2, Y: 0.2, Z: -1}))
+	assertEqual(t, 0, orientation(a, b, c, r3.Vector{X: 0.2, Y: 0.2, Z: 0}))
+	assertEqual(t, -1, orientation(a, b, c, r3.Vector{X: 1e300, Y: -3, Z: -math.SmallestNonzeroFloat64}))
+}
+
+func TestOrientationNearlyCoplanar(t *testing.T) {
+	// Points on the plane x + y + z = 1 can't all be represented exactly, the exact sign must agree with rational arithmetic regardless
+	a := r3.Vector{X: 0.1, Y: 0.2, Z: 0.7}
+	b := r3.Vector{X: 0.3, Y: 0.3, Z: 0.4}
+	c := r3.Vector{X: 0.6, Y: 0.1, Z: 0.3}
+
+	for i := 0; i < 1000; i++ {
+		x := float64(i) / 1000
+		p := r3.Vector{X: x, Y: 0.5 - x/3, Z: 1 - x - (0.5 - x/3)}
+		assertEqual(t, exactOrientation(a, b, c, p), orientation(a, b, c, p))
+
+		p.Z = math.Nextafter(p.Z, 2)
+		assertEqual(t, exactOrientation(a, b, c, p), orientation(a, b, c, p))
+	}
+}
+
+func TestRobustConvexHull(t *testing.T) {
+	pointCloud := horizonFailurePointCloud()
+
+	_, err := new(QuickHull).TryConvexHull(pointCloud, Options{Epsilon: 1e-14})
+	assertErrorKind(t, KindHorizonFailure, err)
+
+	hull, err := new(QuickHull).TryConvexHull(pointCloud, Options{Epsilon: 1e-14, Robust: true, Winding: Clockwise})
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, hull.Diagnostics.FailedHorizonEdges)
+	assertClosedMesh(t, hull)
+
+	// No vertex may be above any face
+	for _, tri := range hull.Triangles() {
+		for _, v := range hull.Vertices {
+			if orientation(tri[0], tri[1], tri[2], v) > 0 {
+				t.Fatalf("vertex %v is above triangle %v", v, tri)
+			}
+		}
+	}
+}
+
+func TestInSphere(t *testing.T) {
+	a := r3.Vector{X: 1, Y: 0, Z: 0}
+	b := r3.Vector{X: 0, Y: 1, Z: 0}
+	c := r3.Vector{X: -1, Y: 0, Z: 0}
+	d := r3.Vector{X: 0, Y: 0, Z: 1}
+	if orientation(a, b, c, d) < 0 {
+		a, b = b, a
+	}
+
+	assertEqual(t, 1, inSphere(a, b, c, d, r3.Vector{X: 0.1, Y: 0.2, Z: -0.3}))
+	assertEqual(t, -1, inSphere(a, b, c, d, r3.Vector{X: 1, Y: 1, Z: 1}))
+	assertEqual(t, 0, inSphere(a, b, c, d, r3.Vector{X: 0, Y: 0, Z: -1}))
+	assertEqual(t, 0, inSphere(a, b, c, d, r3.Vector{X: 0, Y: -1, Z: 0}))
+}
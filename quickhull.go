@@ -1,14 +1,19 @@
 package quickhull
 
 import (
+	"context"
 	"log"
 	"math"
 
 	"github.com/golang/geo/r3"
 )
 
+// DefaultEpsilon is used if the given epsilon is <= 0, here and in the packages built on top of quickhull.
+const DefaultEpsilon = 0.0000001
+
 const (
-	defaultEpsilon = 0.0000001
+	cancellationCheckInterval = 64   // How many iterations of the main loop may pass between checks for context cancellation
+	cancellationCheckPoints   = 1024 // How many points loops over the whole point cloud may process between checks for context cancellation
 )
 
 // QuickHull can be used to calculate the convex hull of a point cloud.
@@ -16,89 +21,280 @@ const (
 type QuickHull struct {
 	epsilon        float64
 	epsilonSquared float64
+	unit           float64 // Scale of the normalized point cloud (see Options.Normalize), epsilon * unit is in the units of the input
 
 	planar               bool
 	planarPointCloudTemp []r3.Vector
 	vertexData           []r3.Vector
 	mesh                 meshBuilder
 	extremeValueIndices  [6]int
-	diagnostics          diagnostics
+	iteration            int       // Iteration counter of the main loop, Faces remember on which iteration their visibility was checked
+	dimension            Dimension // Dimension of the point cloud, the mesh is only a proper hull for DimensionPolytope
+	outline              []int     // Point indices of the hull if it isn't a polytope: the point, the endpoints of the segment or the ordered polygon
+	diagnostics          Diagnostics
+	logger               Logger
 
 	newFaceIndices           []int
 	newHalfEdgeIndices       []int
 	disabledFacePointVectors [][]int
+	newFaces                 []*meshBuilderFace
+	redistributedPoints      []int
+	pointAssignments         []pointAssignment
+	workers                  int
+	robust                   bool
+	retained                 *retainedPoints // Keeps track of the interior points for DynamicHull, nil otherwise
+}
+
+// Diagnostics contains statistics about the construction of a hull.
+type Diagnostics struct {
+	FailedHorizonEdges int     // How many times QuickHull failed to solve the horizon edge. Failures lead to degenerated convex hulls.
+	Iterations         int     // Number of iterations of the main loop
+	FacesCreated       int     // Number of faces created, including the faces of the initial tetrahedron
+	FacesDisabled      int     // Number of faces that were removed from the hull again
+	PointsDiscarded    int     // Number of points that were found to be inside the hull (or within epsilon of its surface) and not considered anymore
+	PointsCulled       int     // Number of points discarded before running the algorithm, see Options.CullInterior
+	JoggleAttempts     int     // Number of times the input was joggled, see Options.Joggle
+	JoggleMagnitude    float64 // Maximum perturbation of each coordinate in the last joggle attempt
+	PlanarFallback     bool    // Whether all points appeared to lie on a plane, in which case an extra point was added to give the hull volume
+}
+
+// Logger receives warnings emitted during the hull construction. *log.Logger implements this interface.
+type Logger interface {
+	Printf(format string, v ...interface{})
 }
 
-type diagnostics struct {
-	failedHorizonEdges int // How many times QuickHull failed to solve the horizon edge. Failures lead to degenerated convex hulls.
+// Logs to the standard logger of the log package.
+type stdLogger struct{}
+
+func (stdLogger) Printf(format string, v ...interface{}) {
+	log.Printf(format, v...)
 }
 
+// Discards all messages.
+type nopLogger struct{}
+
+func (nopLogger) Printf(string, ...interface{}) {}
+
 // ConvexHull calculates the convex hull of the given point cloud using the Quickhull algorithm.
 // If epsilon is <= 0 a default value will be used.
+// Panics if the hull can't be computed, see TryConvexHull for a variant that returns an error instead.
 func (qh *QuickHull) ConvexHull(pointCloud []r3.Vector, ccw bool, useOriginalIndices bool, epsilon float64) ConvexHull {
-	qh.buildMesh(pointCloud, epsilon)
-	return newConvexHull(qh.mesh, qh.vertexData, ccw, useOriginalIndices)
+	hull, err := qh.TryConvexHull(pointCloud, legacyOptions(ccw, useOriginalIndices, epsilon))
+	mustSucceed(err)
+	return hull
 }
 
 // ConvexHull calculates the convex hull of the given point cloud using the Quickhull algorithm and returns it as a HalfEdgeMesh.
 // If epsilon is <= 0 a default value will be used.
+// Panics if the hull can't be computed, see TryConvexHullAsMesh for a variant that returns an error instead.
 func (qh *QuickHull) ConvexHullAsMesh(pointCloud []r3.Vector, epsilon float64) HalfEdgeMesh {
-	qh.buildMesh(pointCloud, epsilon)
-	return newHalfEdgeMesh(qh.mesh, qh.vertexData)
+	mesh, err := qh.TryConvexHullAsMesh(pointCloud, legacyOptions(true, false, epsilon))
+	mustSucceed(err)
+	return mesh
 }
 
-func (qh *QuickHull) buildMesh(pointCloud []r3.Vector, epsilon float64) {
-	if len(pointCloud) == 0 {
-		return
+// TryConvexHull calculates the convex hull of the given point cloud using the Quickhull algorithm.
+// Unlike ConvexHull it returns an error instead of panicking.
+// If the horizon edge could not be solved for some points, the (slightly degenerated) hull is returned together with an error of kind KindHorizonFailure.
+func (qh *QuickHull) TryConvexHull(pointCloud []r3.Vector, opts Options) (ConvexHull, error) {
+	return qh.ConvexHullContext(context.Background(), pointCloud, opts)
+}
+
+// TryConvexHullAsMesh calculates the convex hull of the given point cloud using the Quickhull algorithm and returns it as a HalfEdgeMesh.
+// Unlike ConvexHullAsMesh it returns an error instead of panicking.
+// If the horizon edge could not be solved for some points, the (slightly degenerated) mesh is returned together with an error of kind KindHorizonFailure.
+func (qh *QuickHull) TryConvexHullAsMesh(pointCloud []r3.Vector, opts Options) (HalfEdgeMesh, error) {
+	return qh.ConvexHullAsMeshContext(context.Background(), pointCloud, opts)
+}
+
+// ConvexHullContext is like TryConvexHull but stops early if ctx is done or one of the budgets in opts is exhausted.
+// In that case the partial hull built so far is returned together with ctx.Err() or an error of kind KindIterationLimit / KindFaceLimit.
+// The partial hull is a closed convex mesh, but it doesn't contain all the points.
+func (qh *QuickHull) ConvexHullContext(ctx context.Context, pointCloud []r3.Vector, opts Options) (hull ConvexHull, err error) {
+	defer func() {
+		if e := errorFromPanic(recover()); e != nil {
+			hull, err = ConvexHull{}, e
+		}
+	}()
+
+	err = qh.build(ctx, pointCloud, opts)
+	if err != nil && !isPartialResult(err) {
+		return ConvexHull{}, err
+	}
+
+	hull = newConvexHull(qh.mesh, qh.vertexData, opts, qh.dimension, qh.outline)
+	hull.Diagnostics = qh.diagnostics
+	hull.Epsilon = qh.epsilon * qh.unit
+	return hull, err
+}
+
+// ConvexHullAsMeshContext is like TryConvexHullAsMesh but stops early if ctx is done or one of the budgets in opts is exhausted.
+// In that case the partial mesh built so far is returned together with ctx.Err() or an error of kind KindIterationLimit / KindFaceLimit.
+func (qh *QuickHull) ConvexHullAsMeshContext(ctx context.Context, pointCloud []r3.Vector, opts Options) (mesh HalfEdgeMesh, err error) {
+	defer func() {
+		if e := errorFromPanic(recover()); e != nil {
+			mesh, err = HalfEdgeMesh{}, e
+		}
+	}()
+
+	err = qh.build(ctx, pointCloud, opts)
+	if err != nil && !isPartialResult(err) {
+		return HalfEdgeMesh{}, err
 	}
 
-	if epsilon <= 0 {
-		epsilon = defaultEpsilon
+	mesh = newHalfEdgeMesh(qh.mesh, qh.vertexData, opts, qh.dimension, qh.outline)
+	mesh.Diagnostics = qh.diagnostics
+	mesh.Epsilon = qh.epsilon * qh.unit
+	return mesh, err
+}
+
+// Reports whether err still comes with a usable (but incomplete or slightly degenerated) result.
+func isPartialResult(err error) bool {
+	return err == context.Canceled || err == context.DeadlineExceeded ||
+		isErrorKind(err, KindHorizonFailure) || isErrorKind(err, KindIterationLimit) || isErrorKind(err, KindFaceLimit)
+}
+
+// Panics on errors other than horizon failures (which are only logged) and empty input (which results in an empty hull) to stay compatible with the non-error API.
+func mustSucceed(err error) {
+	if err != nil && !isErrorKind(err, KindHorizonFailure) && !isErrorKind(err, KindDegenerateInput) {
+		panic(err)
 	}
+}
 
-	qh.vertexData = pointCloud
+func (qh *QuickHull) buildMesh(ctx context.Context, pointCloud []r3.Vector, opts Options) error {
+	qh.mesh = meshBuilder{}
 
-	// Very first: find extreme values and use them to compute the scale of the point cloud.
-	qh.extremeValueIndices = extremeValues(qh.vertexData)
-	scale := scale(qh.vertexData, qh.extremeValueIndices) // TODO: maybe pass extreme values
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
 
-	// Epsilon we use depends on the scale
-	qh.epsilon = epsilon * scale
-	qh.epsilonSquared = qh.epsilon * qh.epsilon
+	err := qh.setup(ctx, pointCloud, opts)
+	if err != nil {
+		return err
+	}
 
 	// Reset diagnostics
-	qh.diagnostics = diagnostics{}
+	qh.diagnostics = Diagnostics{}
+
+	qh.workers = opts.Workers
+	qh.robust = opts.Robust
+
+	qh.logger = opts.Logger
+	if qh.logger == nil {
+		qh.logger = nopLogger{}
+	}
+
+	// Optionally get rid of points that can't be part of the hull before doing any real work.
+	var keptPoints []int
+	if opts.CullInterior {
+		keptPoints, err = qh.cullInteriorPoints(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if keptPoints != nil {
+		qh.diagnostics.PointsCulled = len(pointCloud) - len(keptPoints)
+		culled := make([]r3.Vector, len(keptPoints))
+		for i, idx := range keptPoints {
+			culled[i] = qh.vertexData[idx]
+		}
+		qh.vertexData = culled
+		qh.extremeValueIndices = extremeValues(qh.vertexData)
+	}
 
+	qh.iteration = 0
 	qh.planar = false // The planar case happens when all the points appear to lie on a two dimensional subspace of R^3.
-	qh.createConvexHalfEdgeMesh()
+	qh.outline = nil
+	err = qh.createConvexHalfEdgeMesh(ctx, opts)
 
+	qh.diagnostics.PlanarFallback = qh.planar
 	if qh.planar {
 		extraPointIdx := len(qh.planarPointCloudTemp) - 1
+		qh.outline = qh.planarOutline(extraPointIdx)
 		for i := range qh.mesh.halfEdges {
 			if qh.mesh.halfEdges[i].EndVertex == extraPointIdx {
 				qh.mesh.halfEdges[i].EndVertex = 0
 			}
 		}
-		qh.vertexData = pointCloud
 		qh.planarPointCloudTemp = qh.planarPointCloudTemp[:0]
 	}
+
+	if keptPoints != nil {
+		qh.mesh.mapPointIndices(keptPoints)
+		for i, idx := range qh.outline {
+			qh.outline[i] = keptPoints[idx]
+		}
+	}
+	qh.vertexData = pointCloud
+
+	if err != nil {
+		return err
+	}
+
+	if qh.diagnostics.FailedHorizonEdges > 0 {
+		return newError(KindHorizonFailure, "%d points were dropped", qh.diagnostics.FailedHorizonEdges)
+	}
+
+	return nil
 }
 
-// This will update m_mesh from which we create the ConvexHull object that getConvexHull function returns
-func (qh *QuickHull) createConvexHalfEdgeMesh() {
-	var visibleFaces []int
-	var horizontalEdges []int
+// Validates the point cloud and computes its extreme values and the epsilon to use.
+// Leaves the (possibly normalized) points in vertexData.
+func (qh *QuickHull) setup(ctx context.Context, pointCloud []r3.Vector, opts Options) error {
+	if len(pointCloud) == 0 {
+		return newError(KindDegenerateInput, "point cloud is empty")
+	}
 
-	type faceData struct {
-		faceIndex           int
-		enteredFromHalfEdge int // If the Face turns out not to be visible, this half edge will be marked as horizon edge
+	for i, v := range pointCloud {
+		if !isFinite(v) {
+			return newError(KindNonFiniteInput, "point %d is %v", i, v)
+		}
+		if err := checkContext(ctx, i); err != nil {
+			return err
+		}
 	}
 
-	var possiblyVisibleFaces []faceData
+	qh.vertexData = pointCloud
+
+	// Very first: find extreme values and use them to compute the scale of the point cloud.
+	qh.extremeValueIndices = extremeValues(qh.vertexData)
+
+	// Optionally move the point cloud into the unit box, so the epsilon doesn't depend on the position of the point cloud.
+	unit := 1.0
+	if opts.Normalize {
+		qh.vertexData, unit = normalize(pointCloud, qh.extremeValueIndices)
+	}
 
+	scale := scale(qh.vertexData, qh.extremeValueIndices) // TODO: maybe pass extreme values
+
+	// Epsilon we use depends on the scale (unless it's absolute)
+	qh.epsilon = opts.epsilon()
+	if opts.EpsilonMode == RelativeEpsilon {
+		qh.epsilon *= scale
+	} else {
+		qh.epsilon /= unit
+	}
+	qh.epsilonSquared = qh.epsilon * qh.epsilon
+	qh.unit = unit
+
+	return nil
+}
+
+// This will update m_mesh from which we create the ConvexHull object that getConvexHull function returns.
+// Returns early with an error if ctx is done or a budget of opts is exhausted, the mesh is still a valid (partial) hull in that case.
+func (qh *QuickHull) createConvexHalfEdgeMesh(ctx context.Context, opts Options) error {
 	// Compute base tetrahedron
-	qh.mesh = qh.initialTetrahedron()
+	var err error
+	qh.mesh, err = qh.initialTetrahedron(ctx)
+	if err != nil {
+		return err
+	}
 	assertTrue(len(qh.mesh.faces) == 4)
+	if qh.retaining() {
+		qh.retainInitialPoints()
+	}
 
 	var faceList []int
 	for i := 0; i < 4; i++ {
@@ -109,15 +305,109 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 		}
 	}
 
+	return qh.processFaces(ctx, faceList, opts)
+}
+
+// Adds the points vertexData[first:] to the mesh, which must be a proper hull of vertexData[:first] (i.e. not degenerate or planar).
+// Only points outside of the current hull are processed.
+func (qh *QuickHull) extendMesh(ctx context.Context, first int, opts Options) error {
+	failedHorizonEdges := qh.diagnostics.FailedHorizonEdges
+
+	var faceList []int
+	for i := range qh.mesh.faces {
+		f := &qh.mesh.faces[i]
+		if f.isDisabled() {
+			continue
+		}
+		if f.inFaceStack && len(f.pointsOnPositiveSide) > 0 {
+			// Construction was stopped early, continue where we left off
+			faceList = append(faceList, i)
+			continue
+		}
+		// Points left over from failed horizon edges were given up on, just like in a full rebuild
+		f.inFaceStack = false
+		f.pointsOnPositiveSide = nil
+		f.mostDistantPointDist = 0
+	}
+
+	qh.workers = opts.Workers
+	qh.robust = opts.Robust
+
+	points := make([]int, 0, len(qh.vertexData)-first)
+	for i := first; i < len(qh.vertexData); i++ {
+		points = append(points, i)
+	}
+	var faces []*meshBuilderFace
+	var faceIndices []int
+	for i := range qh.mesh.faces {
+		if !qh.mesh.faces[i].isDisabled() {
+			faces = append(faces, &qh.mesh.faces[i])
+			faceIndices = append(faceIndices, i)
+		}
+	}
+	// Not interrupted, AddPoints couldn't continue with the new points otherwise
+	assignments, _ := qh.assignPointsToFaces(context.Background(), points, faces)
+	for _, a := range assignments {
+		if a.face < 0 {
+			qh.diagnostics.PointsDiscarded++
+			continue
+		}
+		if f := faces[a.face]; !f.inFaceStack {
+			faceList = append(faceList, faceIndices[a.face])
+			f.inFaceStack = true
+		}
+	}
+
+	err := qh.processFaces(ctx, faceList, opts)
+	if err != nil {
+		return err
+	}
+
+	if failed := qh.diagnostics.FailedHorizonEdges - failedHorizonEdges; failed > 0 {
+		return newError(KindHorizonFailure, "%d points were dropped", failed)
+	}
+
+	return nil
+}
+
+// Extends the mesh until none of the Faces in faceList (or the Faces created along the way) has points on its positive side.
+func (qh *QuickHull) processFaces(ctx context.Context, faceList []int, opts Options) error {
+	var visibleFaces []int
+	var horizontalEdges []int
+
+	type faceData struct {
+		faceIndex           int
+		enteredFromHalfEdge int // If the Face turns out not to be visible, this half edge will be marked as horizon edge
+	}
+
+	var possiblyVisibleFaces []faceData
+
 	// Process Faces until the Face list is empty.
-	iter := 0
+	var nIterations int
 	for len(faceList) > 0 {
-		iter++
-		if iter == maxInt {
+		if nIterations%cancellationCheckInterval == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+		if opts.MaxIterations > 0 && nIterations >= opts.MaxIterations {
+			return newError(KindIterationLimit, "stopped after %d iterations", nIterations)
+		}
+		if nFaces := len(qh.mesh.faces) - len(qh.mesh.disabledFaces); opts.MaxFaces > 0 && nFaces >= opts.MaxFaces {
+			return newError(KindFaceLimit, "stopped at %d faces", nFaces)
+		}
+
+		nIterations++
+		qh.diagnostics.Iterations++
+		qh.iteration++
+		if qh.iteration == maxInt {
 			// Visible Face traversal marks visited Faces with iteration counter (to mark that the Face has been visited on this iteration) and the max value represents unvisited Faces. At this point we have to reset iteration counter. This shouldn't be an
 			// issue on 64 bit machines.
-			iter = 0
+			qh.iteration = 0
 		}
+		iter := qh.iteration
 
 		var topFaceIndex int
 		topFaceIndex, faceList = faceList[0], faceList[1:]
@@ -153,10 +443,8 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 					continue
 				}
 			} else {
-				p := pvf.plane
 				pvf.visibilityCheckedOnIteration = iter
-				d := p.n.Dot(activePoint) + p.d
-				if d > 0 {
+				if qh.isOnPositiveSide(pvf, activePointIndex) {
 					pvf.isVisibleFaceOnCurrentIteration = true
 					pvf.horizonEdgesOnCurrentIteration = 0
 					visibleFaces = append(visibleFaces, fd.faceIndex)
@@ -193,8 +481,8 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 
 		// Order horizon edges so that they form a loop. This may fail due to numerical instability in which case we give up trying to solve horizon edge for this point and accept a minor degeneration in the convex hull.
 		if !qh.reorderHorizontalEdges(horizontalEdges) {
-			qh.diagnostics.failedHorizonEdges++
-			log.Println("Failed to solve horizon edge")
+			qh.diagnostics.FailedHorizonEdges++
+			qh.logger.Printf("quickhull: failed to solve horizon edge for point %d", activePointIndex)
 
 			for i := range tf.pointsOnPositiveSide {
 				if tf.pointsOnPositiveSide[i] == activePointIndex {
@@ -212,6 +500,10 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 			continue
 		}
 
+		if qh.retaining() {
+			qh.releaseInteriorPoints(visibleFaces)
+		}
+
 		// Except for the horizon edges, all half edges of the visible Faces can be marked as disabled. Their data slots will be reused.
 		// The Faces will be disabled as well, but we need to remember the points that were on the positive side of them - therefore
 		// we save pointers to them.
@@ -238,6 +530,7 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 			// Disable the Face, but retain pointer to the points that were on the positive side of it. We need to assign those points
 			// to the new Faces we create shortly.
 			t := qh.mesh.disableFace(faceIdx)
+			qh.diagnostics.FacesDisabled++
 			if t != nil {
 				assertTrue(len(t) > 0)
 				qh.disabledFacePointVectors = append(qh.disabledFacePointVectors, t)
@@ -257,6 +550,7 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 			a, b, c := horizonEdgeVertexIndices[0], horizonEdgeVertexIndices[1], activePointIndex
 
 			newFaceIdx := qh.mesh.addFace()
+			qh.diagnostics.FacesCreated++
 			qh.newFaceIndices = append(qh.newFaceIndices, newFaceIdx)
 
 			ca, bc := qh.newHalfEdgeIndices[2*i+0], qh.newHalfEdgeIndices[2*i+1]
@@ -288,16 +582,12 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 			qh.mesh.halfEdges[bc].Opp = qh.newHalfEdgeIndices[((i+1)*2)%(nHorizontalEdges*2)]
 		}
 
+		qh.redistributedPoints = qh.redistributedPoints[:0]
 		for _, disabledPoints := range qh.disabledFacePointVectors {
 			assertTrue(disabledPoints != nil)
 			for _, pointIdx := range disabledPoints {
-				if pointIdx == activePointIndex {
-					continue
-				}
-				for i := 0; i < nHorizontalEdges; i++ {
-					if qh.addPointToFace(&qh.mesh.faces[qh.newFaceIndices[i]], pointIdx) {
-						break
-					}
+				if pointIdx != activePointIndex {
+					qh.redistributedPoints = append(qh.redistributedPoints, pointIdx)
 				}
 			}
 			/* TODO: optimize
@@ -305,6 +595,24 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 			reclaimToIndexVectorPool(disabledPoints);
 			*/
 		}
+		qh.newFaces = qh.newFaces[:0]
+		for _, newFaceIdx := range qh.newFaceIndices {
+			qh.newFaces = append(qh.newFaces, &qh.mesh.faces[newFaceIdx])
+		}
+		// Not interrupted, the points of the disabled Faces would be lost otherwise
+		assignments, _ := qh.assignPointsToFaces(context.Background(), qh.redistributedPoints, qh.newFaces)
+		for k, a := range assignments {
+			if a.face < 0 {
+				qh.diagnostics.PointsDiscarded++
+				if qh.retaining() {
+					qh.retained.pending = append(qh.retained.pending, qh.redistributedPoints[k])
+				}
+			}
+		}
+		if qh.retaining() {
+			qh.retainInteriorPoints(qh.newFaceIndices)
+		}
+
 		// Increase Face stack size if needed
 		for _, newFaceIdx := range qh.newFaceIndices {
 			newFace := &qh.mesh.faces[newFaceIdx]
@@ -322,12 +630,22 @@ func (qh *QuickHull) createConvexHalfEdgeMesh() {
 	// Cleanup
 	m_indexVectorPool.clear();
 	*/
+
+	return nil
 }
 
 // Create a half edge mesh representing the base tetrahedron from which the QuickHull iteration proceeds. m_extremeValues must be properly set up when this is called.
-func (qh *QuickHull) initialTetrahedron() meshBuilder {
+// If ctx is done before all points are assigned to the Faces, an empty mesh is returned together with ctx.Err(),
+// as the tetrahedron alone can't be extended with the remaining points later.
+func (qh *QuickHull) initialTetrahedron(ctx context.Context) (meshBuilder, error) {
 	nVertices := len(qh.vertexData)
 
+	dim, spanning := qh.spanningPoints()
+	qh.dimension = dim
+	if dim < DimensionPolytope {
+		qh.outline = append([]int(nil), spanning[:dim+1]...)
+	}
+
 	// If we have at most 3 points, just return p1 degenerate tetrahedron:
 	if nVertices <= 3 {
 		v := [4]int{
@@ -343,42 +661,16 @@ func (qh *QuickHull) initialTetrahedron() meshBuilder {
 		if trianglePlane.isPointOnPositiveSide(qh.vertexData[v[3]]) {
 			v[0], v[1] = v[1], v[0]
 		}
-		return newMeshBuilder(v[0], v[1], v[2], v[3])
-	}
-
-	// Find two most distant extreme points.
-	maxD := qh.epsilonSquared
-	var p1, p2 int
-	for i := 0; i < 6; i++ {
-		for j := i + 1; j < 6; j++ {
-			dv := qh.vertexData[qh.extremeValueIndices[i]].Sub(qh.vertexData[qh.extremeValueIndices[j]])
-			dSq := dv.X*dv.X + dv.Y*dv.Y + dv.Z*dv.Z
-			if dSq > maxD {
-				maxD = dSq
-				p1 = qh.extremeValueIndices[i]
-				p2 = qh.extremeValueIndices[j]
-			}
-		}
+		return newMeshBuilder(v[0], v[1], v[2], v[3]), nil
 	}
 
-	if maxD == qh.epsilonSquared {
+	if dim == DimensionPoint {
 		// A degenerate case: the point cloud seems to consists of p1 single point
-		return newMeshBuilder(0, int(math.Min(1, float64(nVertices-1))), int(math.Min(2, float64(nVertices-1))), int(math.Min(3, float64(nVertices-1))))
+		return newMeshBuilder(0, int(math.Min(1, float64(nVertices-1))), int(math.Min(2, float64(nVertices-1))), int(math.Min(3, float64(nVertices-1)))), nil
 	}
-	assertTrue(p1 != p2)
 
-	// Find the most distant point to the line between the two chosen extreme points.
-	r := newRay(qh.vertexData[p1], qh.vertexData[p2].Sub(qh.vertexData[p1]))
-	maxD = qh.epsilonSquared
-	maxI := maxInt
-	for i, v := range qh.vertexData {
-		distToRay := squaredDistanceBetweenPointAndRay(v, r)
-		if distToRay > maxD {
-			maxD = distToRay
-			maxI = i
-		}
-	}
-	if maxD == qh.epsilonSquared {
+	p1, p2 := spanning[0], spanning[1]
+	if dim == DimensionSegment {
 		// It appears that the point cloud belongs to a 1 dimensional subspace of R^3: convex hull has no volume => return a thin triangle
 		// Pick any point other than selectedPoints.first and selectedPoints.second as the third point of the triangle
 		var it r3.Vector
@@ -403,31 +695,19 @@ func (qh *QuickHull) initialTetrahedron() meshBuilder {
 		if it == qh.vertexData[len(qh.vertexData)-1] {
 			p4 = p1
 		}
-		return newMeshBuilder(p1, p2, p3, p4)
+		return newMeshBuilder(p1, p2, p3, p4), nil
 	}
 
 	// These three points form the base triangle for our tetrahedron.
-	assertTrue(p1 != maxI && p2 != maxI)
-	baseTriangle := [3]int{p1, p2, maxI}
+	baseTriangle := [3]int{p1, p2, spanning[2]}
 	baseTriangleVertices := [3]r3.Vector{qh.vertexData[baseTriangle[0]], qh.vertexData[baseTriangle[1]], qh.vertexData[baseTriangle[2]]}
 
 	// Next step is to find the 4th vertex of the tetrahedron.
 	// We naturally choose the point farthest away from the triangle plane.
-	maxD = qh.epsilon
-	maxI = 0
+	maxI := spanning[3]
 	{
 		n := triangleNormal(baseTriangleVertices[0], baseTriangleVertices[1], baseTriangleVertices[2])
-		{
-			trianglePlane := newPlane(n, baseTriangleVertices[0])
-			for i := 0; i < nVertices; i++ {
-				d := math.Abs(signedDistanceToPlane(qh.vertexData[i], trianglePlane))
-				if d > maxD {
-					maxD = d
-					maxI = i
-				}
-			}
-		}
-		if maxD == qh.epsilon {
+		if dim == DimensionPolygon {
 			// All the points seem to lie on a 2D subspace of R^3. How to handle this?
 			// Well, let's add one extra point to the point cloud so that the convex hull will have volume.
 			qh.planar = true
@@ -459,34 +739,38 @@ func (qh *QuickHull) initialTetrahedron() meshBuilder {
 	}
 
 	// Finally we assign a Face for each vertex outside the tetrahedron (Vertices inside the tetrahedron have no role anymore)
-	for i := 0; i < nVertices; i++ {
-		for j := range mesh.faces {
-			if qh.addPointToFace(&mesh.faces[j], i) {
-				break
+	qh.mesh = mesh // Robust predicates need the vertices of the Faces
+	qh.diagnostics.FacesCreated += len(mesh.faces)
+	points := make([]int, nVertices)
+	for i := range points {
+		points[i] = i
+	}
+	faces := make([]*meshBuilderFace, len(mesh.faces))
+	for i := range mesh.faces {
+		faces[i] = &mesh.faces[i]
+	}
+	assignments, err := qh.assignPointsToFaces(ctx, points, faces)
+	if err != nil {
+		return meshBuilder{}, err
+	}
+	for i, a := range assignments {
+		if a.face < 0 && i != baseTriangle[0] && i != baseTriangle[1] && i != baseTriangle[2] && i != maxI {
+			qh.diagnostics.PointsDiscarded++
+			if qh.retaining() {
+				qh.retained.pending = append(qh.retained.pending, i)
 			}
 		}
 	}
 
-	return mesh
+	return mesh, nil
 }
 
-// Associates a point with a Face if the point resides on the positive side of the plane. Returns true if the points was on the positive side.
-func (qh *QuickHull) addPointToFace(face *meshBuilderFace, pointIndex int) bool {
-	d := signedDistanceToPlane(qh.vertexData[pointIndex], face.plane)
-	if d > 0 && d*d > qh.epsilonSquared*face.plane.sqrNLength {
-		/* TODO: optimize
-		if Face.pointsOnPositiveSide == nil {
-			f.m_pointsOnPositiveSide = std::move(getIndexVectorFromPool());
-		}
-		*/
-		face.pointsOnPositiveSide = append(face.pointsOnPositiveSide, pointIndex)
-		if d > face.mostDistantPointDist {
-			face.mostDistantPointDist = d
-			face.mostDistantPoint = pointIndex
-		}
-		return true
+// Returns ctx.Err() once every cancellationCheckPoints points, i is the index of the current point.
+func checkContext(ctx context.Context, i int) error {
+	if i%cancellationCheckPoints != cancellationCheckPoints-1 {
+		return nil
 	}
-	return false
+	return ctx.Err()
 }
 
 // Given a list of half edges, try to rearrange them so that they form a loop. Return true on success.
@@ -510,8 +794,9 @@ func (qh QuickHull) reorderHorizontalEdges(horizontalEdges []int) bool {
 	return true
 }
 
+// Panics with an error of kind KindInternal, which is recovered and returned by the error-returning API.
 func assertTrue(b bool) {
 	if !b {
-		panic("Assertion failed")
+		panic(newError(KindInternal, "assertion failed"))
 	}
 }
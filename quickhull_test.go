@@ -1,9 +1,12 @@
 package quickhull
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -60,6 +63,14 @@ func assertEqual(t *testing.T, expected, actual interface{}) {
 	}
 }
 
+func assertAlmostEqual(t *testing.T, expected, actual, delta float64) {
+	t.Helper()
+
+	if math.Abs(expected-actual) > delta {
+		t.Errorf("assertion failed: %v != %v (delta %v)", expected, actual, delta)
+	}
+}
+
 // Simple 2D test (square, all points on a plane)
 func TestConvexHull2DSquare(t *testing.T) {
 	// Construct a square as 'point cloud' that looks roughly like this.
@@ -234,3 +245,689 @@ func TestPlanes(t *testing.T) {
 func convexHull(pointCloud []r3.Vector) ConvexHull {
 	return new(QuickHull).ConvexHull(pointCloud, true, false, 0)
 }
+
+func TestTryConvexHullEmpty(t *testing.T) {
+	_, err := new(QuickHull).TryConvexHull(nil, Options{})
+
+	assertErrorKind(t, KindDegenerateInput, err)
+}
+
+func TestTryConvexHullNonFinite(t *testing.T) {
+	pointCloud := []r3.Vector{
+		{X: 0, Y: 0, Z: 0},
+		{X: 1, Y: 0, Z: 0},
+		{X: 0, Y: math.NaN(), Z: 0},
+		{X: 0, Y: 0, Z: 1},
+	}
+
+	_, err := new(QuickHull).TryConvexHull(pointCloud, Options{})
+
+	assertErrorKind(t, KindNonFiniteInput, err)
+}
+
+func TestErrorFromPanic(t *testing.T) {
+	assertEqual(t, nil, errorFromPanic(nil))
+
+	err := func() (err error) {
+		defer func() {
+			err = errorFromPanic(recover())
+		}()
+		assertTrue(false)
+		return nil
+	}()
+	assertErrorKind(t, KindInternal, err)
+
+	err = func() (err error) {
+		defer func() {
+			err = errorFromPanic(recover())
+		}()
+		var s []int
+		_ = s[1]
+		return nil
+	}()
+	assertErrorKind(t, KindInternal, err)
+}
+
+func TestErrorIs(t *testing.T) {
+	err := newError(KindHorizonFailure, "%d points were dropped", 3)
+
+	assertEqual(t, true, err.Is(ErrHorizonFailure))
+	assertEqual(t, false, err.Is(ErrInternal))
+	assertEqual(t, "quickhull: failed to solve horizon edge: 3 points were dropped", err.Error())
+}
+
+// Point clouds for which the output of the original implementation was recorded in TestOptionsDefaultMatchesLegacy.
+func goldenPointClouds() map[string][]r3.Vector {
+	r := rand.New(rand.NewSource(42))
+	random := make([]r3.Vector, 30)
+	for i := range random {
+		random[i] = r3.Vector{X: 2*r.Float64() - 1, Y: 2*r.Float64() - 1, Z: 2*r.Float64() - 1}
+	}
+
+	return map[string][]r3.Vector{
+		"point":    {{X: 1, Y: 2, Z: 3}, {X: 1, Y: 2, Z: 3}, {X: 1, Y: 2, Z: 3}, {X: 1, Y: 2, Z: 3}},
+		"segment":  {{X: 0, Y: 0, Z: 0}, {X: 1, Y: 1, Z: 1}, {X: 0.5, Y: 0.5, Z: 0.5}, {X: 0.25, Y: 0.25, Z: 0.25}},
+		"triangle": {{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}, {X: 0, Y: 1, Z: 0}},
+		"planar":   {{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}, {X: 1, Y: 1, Z: 0}, {X: 0, Y: 1, Z: 0}, {X: 0.5, Y: 0.5, Z: 0}, {X: 0.25, Y: 0.75, Z: 0}},
+		"nearly coplanar": {{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}, {X: 1, Y: 1, Z: 0}, {X: 0, Y: 1, Z: 0},
+			{X: 0.5, Y: 0.5, Z: 1e-9}, {X: 0.3, Y: 0.6, Z: -1e-9}, {X: 0.7, Y: 0.2, Z: 1e-6}},
+		"cube": {{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}, {X: 1, Y: 1, Z: 0}, {X: 0, Y: 1, Z: 0},
+			{X: 0, Y: 0, Z: 1}, {X: 1, Y: 0, Z: 1}, {X: 1, Y: 1, Z: 1}, {X: 0, Y: 1, Z: 1}, {X: 0.5, Y: 0.5, Z: 0.5}},
+		"random": random,
+	}
+}
+
+// Default options must reproduce the results of the implementation before Options were introduced.
+// The expected outputs were recorded with the original ConvexHull(pointCloud, ccw, useOriginalIndices, epsilon).
+func TestOptionsDefaultMatchesLegacy(t *testing.T) {
+	clouds := goldenPointClouds()
+
+	for _, golden := range []struct {
+		cloud    string
+		opts     Options
+		vertices []int // Indices into the point cloud of the optimized vertices, nil for OriginalIndices
+		indices  []int
+	}{
+		{"point", Options{Winding: CounterClockwise, IndexMode: CompactIndices}, []int{0, 0, 0, 0}, []int{0, 2, 1, 1, 2, 3, 2, 0, 3, 0, 1, 3}},
+		{"point", Options{Winding: Clockwise, IndexMode: OriginalIndices}, nil, []int{1, 2, 0, 2, 3, 0, 0, 3, 1, 1, 3, 2}},
+		{"point", Options{Winding: CounterClockwise, IndexMode: OriginalIndices, Epsilon: 0.001}, nil, []int{1, 0, 2, 2, 0, 3, 0, 1, 3, 1, 2, 3}},
+		{"segment", Options{Winding: CounterClockwise, IndexMode: CompactIndices}, []int{0, 1}, []int{0, 1, 1, 1, 1, 1, 1, 0, 1, 0, 1, 1}},
+		{"segment", Options{Winding: Clockwise, IndexMode: OriginalIndices}, nil, []int{0, 1, 1, 1, 1, 1, 1, 1, 0, 0, 1, 1}},
+		{"segment", Options{Winding: CounterClockwise, IndexMode: OriginalIndices, Epsilon: 0.001}, nil, []int{0, 1, 1, 1, 1, 1, 1, 0, 1, 0, 1, 1}},
+		{"triangle", Options{Winding: CounterClockwise, IndexMode: CompactIndices}, []int{0, 2, 1}, []int{0, 2, 1, 1, 2, 1, 2, 0, 1, 0, 1, 1}},
+		{"triangle", Options{Winding: Clockwise, IndexMode: OriginalIndices}, nil, []int{0, 2, 1, 2, 2, 1, 1, 2, 0, 0, 2, 2}},
+		{"triangle", Options{Winding: CounterClockwise, IndexMode: OriginalIndices, Epsilon: 0.001}, nil, []int{0, 1, 2, 2, 1, 2, 1, 0, 2, 0, 2, 2}},
+		{"planar", Options{Winding: CounterClockwise, IndexMode: CompactIndices}, []int{0, 1, 2, 3}, []int{0, 2, 1, 1, 2, 0, 0, 2, 3, 2, 0, 3, 0, 0, 3, 0, 1, 0}},
+		{"planar", Options{Winding: Clockwise, IndexMode: OriginalIndices}, nil, []int{0, 1, 2, 1, 0, 2, 0, 3, 2, 2, 3, 0, 0, 3, 0, 0, 0, 1}},
+		{"planar", Options{Winding: CounterClockwise, IndexMode: OriginalIndices, Epsilon: 0.001}, nil, []int{0, 2, 1, 1, 2, 0, 0, 2, 3, 2, 0, 3, 0, 0, 3, 0, 1, 0}},
+		{"nearly coplanar", Options{Winding: CounterClockwise, IndexMode: CompactIndices}, []int{2, 1, 0, 6, 3}, []int{0, 2, 1, 1, 2, 3, 3, 2, 4, 2, 0, 4, 0, 3, 4, 0, 1, 3}},
+		{"nearly coplanar", Options{Winding: Clockwise, IndexMode: OriginalIndices}, nil, []int{2, 1, 0, 1, 6, 0, 6, 3, 0, 0, 3, 2, 2, 3, 6, 2, 6, 1}},
+		{"nearly coplanar", Options{Winding: CounterClockwise, IndexMode: OriginalIndices, Epsilon: 0.001}, nil, []int{0, 2, 1, 1, 2, 0, 0, 2, 3, 2, 0, 3, 0, 0, 3, 0, 1, 0}},
+		{"cube", Options{Winding: CounterClockwise, IndexMode: CompactIndices}, []int{4, 3, 0, 2, 7, 6, 5, 1}, []int{0, 2, 1, 2, 3, 1, 1, 3, 4, 4, 3, 5, 3, 6, 5, 6, 4, 5, 6, 0, 4, 0, 1, 4, 7, 0, 6, 3, 7, 6, 3, 2, 7, 2, 0, 7}},
+		{"cube", Options{Winding: Clockwise, IndexMode: OriginalIndices}, nil, []int{4, 3, 0, 0, 3, 2, 3, 7, 2, 7, 6, 2, 2, 6, 5, 5, 6, 7, 5, 7, 4, 4, 7, 3, 1, 5, 4, 2, 5, 1, 2, 1, 0, 0, 1, 4}},
+		{"cube", Options{Winding: CounterClockwise, IndexMode: OriginalIndices, Epsilon: 0.001}, nil, []int{4, 0, 3, 0, 2, 3, 3, 2, 7, 7, 2, 6, 2, 5, 6, 5, 7, 6, 5, 4, 7, 4, 3, 7, 1, 4, 5, 2, 1, 5, 2, 0, 1, 0, 4, 1}},
+		{"random", Options{Winding: CounterClockwise, IndexMode: CompactIndices}, []int{9, 1, 29, 14, 8, 22, 11, 21, 19, 25, 20, 12, 6, 16, 17, 26}, []int{0, 2, 1, 2, 3, 1, 1, 3, 4, 3, 2, 4, 2, 5, 4, 5, 6, 4, 6, 7, 4, 7, 8, 4, 8, 1, 4, 8, 0, 1, 0, 8, 9, 8, 7, 9, 9, 7, 10, 7, 6, 10, 6, 11, 10, 11, 12, 10, 12, 9, 10, 12, 0, 9, 0, 12, 13, 12, 14, 13, 14, 0, 13, 14, 2, 0, 2, 14, 5, 14, 15, 5, 15, 6, 5, 12, 6, 15, 14, 12, 15, 6, 12, 11}},
+		{"random", Options{Winding: Clockwise, IndexMode: OriginalIndices}, nil, []int{9, 1, 29, 29, 1, 14, 1, 8, 14, 14, 8, 29, 29, 8, 22, 22, 8, 11, 11, 8, 21, 21, 8, 19, 19, 8, 1, 19, 1, 9, 9, 25, 19, 19, 25, 21, 25, 20, 21, 21, 20, 11, 11, 20, 12, 12, 20, 6, 6, 20, 25, 6, 25, 9, 9, 16, 6, 6, 16, 17, 17, 16, 9, 17, 9, 29, 29, 22, 17, 17, 22, 26, 26, 22, 11, 6, 26, 11, 17, 26, 6, 11, 12, 6}},
+		{"random", Options{Winding: CounterClockwise, IndexMode: OriginalIndices, Epsilon: 0.001}, nil, []int{9, 29, 1, 29, 14, 1, 1, 14, 8, 14, 29, 8, 29, 22, 8, 22, 11, 8, 11, 21, 8, 21, 19, 8, 19, 1, 8, 19, 9, 1, 9, 19, 25, 19, 21, 25, 25, 21, 20, 21, 11, 20, 11, 12, 20, 12, 6, 20, 6, 25, 20, 6, 9, 25, 9, 6, 16, 6, 17, 16, 17, 9, 16, 17, 29, 9, 29, 17, 22, 17, 26, 22, 26, 11, 22, 6, 11, 26, 17, 6, 26, 11, 6, 12}},
+	} {
+		pointCloud := clouds[golden.cloud]
+		expectedVertices := pointCloud
+		if golden.vertices != nil {
+			expectedVertices = make([]r3.Vector, len(golden.vertices))
+			for i, idx := range golden.vertices {
+				expectedVertices[i] = pointCloud[idx]
+			}
+		}
+
+		hull, err := new(QuickHull).TryConvexHull(pointCloud, golden.opts)
+		if err != nil && !isErrorKind(err, KindHorizonFailure) {
+			t.Errorf("%s: unexpected error %v", golden.cloud, err)
+		}
+		if hull.Dimension < DimensionPolygon {
+			// Only the legacy functions output points and segments as degenerate tetrahedron
+			assertEqual(t, 0, len(hull.Indices))
+		} else {
+			assertEqual(t, expectedVertices, hull.Vertices)
+			assertEqual(t, golden.indices, hull.Indices)
+		}
+
+		legacy := new(QuickHull).ConvexHull(pointCloud, golden.opts.Winding == CounterClockwise, golden.opts.IndexMode == OriginalIndices, golden.opts.Epsilon)
+		assertEqual(t, expectedVertices, legacy.Vertices)
+		assertEqual(t, golden.indices, legacy.Indices)
+	}
+}
+
+func TestOptionsComputeNormals(t *testing.T) {
+	pointCloud := randomPointCloud(200)
+
+	for _, winding := range []Winding{CounterClockwise, Clockwise} {
+		hull, err := new(QuickHull).TryConvexHull(pointCloud, Options{Winding: winding, ComputeNormals: true})
+		assertEqual(t, nil, err)
+		assertEqual(t, len(hull.Indices)/3, len(hull.Normals))
+
+		for i, tri := range hull.Triangles() {
+			// The origin is inside the hull, so normals must point away from it
+			if hull.Normals[i].Dot(tri[0]) <= 0 {
+				t.Errorf("normal %v of triangle %v points into the hull", hull.Normals[i], tri)
+			}
+		}
+	}
+
+	mesh, err := new(QuickHull).TryConvexHullAsMesh(pointCloud, Options{ComputeNormals: true})
+	assertEqual(t, nil, err)
+	assertEqual(t, len(mesh.Faces), len(mesh.Normals))
+}
+
+func TestOptionsAbsoluteEpsilon(t *testing.T) {
+	// The point in the middle of the top face is 0.5 above the plane of the other top corners
+	pointCloud := []r3.Vector{
+		{X: 0, Y: 0, Z: 0},
+		{X: 100, Y: 0, Z: 0},
+		{X: 0, Y: 100, Z: 0},
+		{X: 100, Y: 100, Z: 0},
+		{X: 0, Y: 0, Z: 100},
+		{X: 100, Y: 0, Z: 100},
+		{X: 0, Y: 100, Z: 100},
+		{X: 100, Y: 100, Z: 100},
+		{X: 50, Y: 50, Z: 100.5},
+	}
+
+	hull, err := new(QuickHull).TryConvexHull(pointCloud, Options{Epsilon: 0.01})
+	assertEqual(t, nil, err)
+	assertEqual(t, 8, len(hull.Vertices)) // relative epsilon: 0.01 * 100.5 > 0.5
+
+	hull, err = new(QuickHull).TryConvexHull(pointCloud, Options{Epsilon: 0.01, EpsilonMode: AbsoluteEpsilon})
+	assertEqual(t, nil, err)
+	assertEqual(t, 9, len(hull.Vertices))
+}
+
+func TestOptionsMeshOriginalIndices(t *testing.T) {
+	pointCloud := randomPointCloud(200)
+
+	mesh, err := new(QuickHull).TryConvexHullAsMesh(pointCloud, Options{IndexMode: OriginalIndices})
+	assertEqual(t, nil, err)
+	assertEqual(t, len(pointCloud), len(mesh.Vertices))
+
+	compact := new(QuickHull).ConvexHullAsMesh(pointCloud, 0)
+	assertEqual(t, len(compact.HalfEdges), len(mesh.HalfEdges))
+	for i, he := range mesh.HalfEdges {
+		assertEqual(t, compact.Vertices[compact.HalfEdges[i].EndVertex], mesh.Vertices[he.EndVertex])
+	}
+}
+
+func TestConvexHullContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	hull, err := new(QuickHull).ConvexHullContext(ctx, randomPointCloud(1000), Options{})
+
+	assertEqual(t, context.Canceled, err)
+	assertEqual(t, 4, len(hull.Indices)/3) // Only the initial tetrahedron
+}
+
+// Context that is canceled once Err was called n times, to cancel in the middle of a loop.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (ctx *countdownContext) Err() error {
+	ctx.n--
+	if ctx.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestConvexHullContextCanceledDuringSetup(t *testing.T) {
+	pointCloud := randomPointCloud(10 * cancellationCheckPoints)
+
+	// The first check happens while validating the input
+	hull, err := new(QuickHull).ConvexHullContext(&countdownContext{Context: context.Background()}, pointCloud, Options{})
+	assertEqual(t, context.Canceled, err)
+	assertEqual(t, 0, len(hull.Indices))
+
+	// After 10 checks during validation, the next one happens while assigning the points to the initial tetrahedron
+	hull, err = new(QuickHull).ConvexHullContext(&countdownContext{Context: context.Background(), n: 10}, pointCloud, Options{})
+	assertEqual(t, context.Canceled, err)
+	assertEqual(t, 0, len(hull.Indices))
+
+	// Without cancellation all checks pass
+	hull, err = new(QuickHull).ConvexHullContext(&countdownContext{Context: context.Background(), n: 20}, pointCloud, Options{})
+	assertEqual(t, nil, err)
+	assertEqual(t, convexHull(pointCloud).Indices, hull.Indices)
+}
+
+func TestConvexHullContextIterationLimit(t *testing.T) {
+	pointCloud := randomPointCloud(1000)
+
+	hull, err := new(QuickHull).ConvexHullContext(context.Background(), pointCloud, Options{MaxIterations: 5})
+	assertErrorKind(t, KindIterationLimit, err)
+
+	full := convexHull(pointCloud)
+	if len(hull.Vertices) >= len(full.Vertices) {
+		t.Errorf("expected partial hull with less than %d vertices, got %d", len(full.Vertices), len(hull.Vertices))
+	}
+	assertClosedMesh(t, hull)
+}
+
+func TestConvexHullContextFaceLimit(t *testing.T) {
+	mesh, err := new(QuickHull).ConvexHullAsMeshContext(context.Background(), randomPointCloud(1000), Options{MaxFaces: 20})
+
+	assertErrorKind(t, KindFaceLimit, err)
+	if len(mesh.Faces) < 20 || len(mesh.Faces) > 40 {
+		t.Errorf("expected roughly 20 faces, got %d", len(mesh.Faces))
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	pointCloud := []r3.Vector{
+		{X: 0, Y: 0, Z: 0},
+		{X: 0, Y: 0, Z: 10},
+		{X: 0, Y: 10, Z: 0},
+		{X: 0, Y: 10, Z: 10},
+		{X: 10, Y: 0, Z: 0},
+		{X: 10, Y: 0, Z: 10},
+		{X: 10, Y: 10, Z: 0},
+		{X: 10, Y: 10, Z: 10},
+		{X: 5, Y: 5, Z: 5},
+	}
+
+	hull := convexHull(pointCloud)
+
+	diag := hull.Diagnostics
+	assertEqual(t, 0, diag.FailedHorizonEdges)
+	assertEqual(t, false, diag.PlanarFallback)
+	assertEqual(t, 1, diag.PointsDiscarded)
+	assertEqual(t, len(hull.Indices)/3, diag.FacesCreated-diag.FacesDisabled)
+	if diag.Iterations < 4 {
+		t.Errorf("expected at least 4 iterations, got %d", diag.Iterations)
+	}
+
+	assertEqual(t, true, convexHull(pointCloud[:4]).Diagnostics.PlanarFallback)
+}
+
+type recordingLogger []string
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	*l = append(*l, fmt.Sprintf(format, v...))
+}
+
+func TestLogger(t *testing.T) {
+	var logger recordingLogger
+
+	hull, err := new(QuickHull).TryConvexHull(horizonFailurePointCloud(), Options{Epsilon: 1e-14, Logger: &logger})
+
+	assertErrorKind(t, KindHorizonFailure, err)
+	assertEqual(t, hull.Diagnostics.FailedHorizonEdges, len(logger))
+	if len(logger) == 0 {
+		t.Error("expected failed horizon edges to be logged")
+	}
+}
+
+func TestJoggle(t *testing.T) {
+	pointCloud := horizonFailurePointCloud()
+	opts := Options{Epsilon: 1e-14, Joggle: true, JoggleSeed: 42}
+
+	hull, err := new(QuickHull).TryConvexHull(pointCloud, opts)
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, hull.Diagnostics.FailedHorizonEdges)
+	assertClosedMesh(t, hull)
+	if hull.Diagnostics.JoggleAttempts == 0 || hull.Diagnostics.JoggleMagnitude <= 0 {
+		t.Errorf("expected joggle to be reported in diagnostics, got %+v", hull.Diagnostics)
+	}
+
+	// Output must consist of the original points
+	original := make(map[r3.Vector]bool)
+	for _, v := range pointCloud {
+		original[v] = true
+	}
+	for _, v := range hull.Vertices {
+		if !original[v] {
+			t.Errorf("vertex %v is not part of the input", v)
+		}
+	}
+
+	again, err := new(QuickHull).TryConvexHull(pointCloud, opts)
+	assertEqual(t, nil, err)
+	assertEqual(t, hull, again)
+
+	// Joggling only happens when necessary
+	hull, err = new(QuickHull).TryConvexHull(randomPointCloud(100), opts)
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, hull.Diagnostics.JoggleAttempts)
+}
+
+func TestNormalizeTranslationInvariant(t *testing.T) {
+	pointCloud := randomPointCloud(500)
+
+	translated := make([]r3.Vector, len(pointCloud))
+	for i, v := range pointCloud {
+		translated[i] = v.Add(r3.Vector{X: 1e6, Y: -2e6, Z: 3e6})
+	}
+
+	opts := Options{IndexMode: OriginalIndices, Normalize: true}
+
+	expected, err := new(QuickHull).TryConvexHull(pointCloud, opts)
+	assertEqual(t, nil, err)
+	actual, err := new(QuickHull).TryConvexHull(translated, opts)
+	assertEqual(t, nil, err)
+	assertEqual(t, hullVertexIndices(expected), hullVertexIndices(actual))
+
+	// Without normalization the epsilon depends on the distance from the origin and vertices get lost
+	unnormalized, err := new(QuickHull).TryConvexHull(translated, Options{IndexMode: OriginalIndices})
+	assertEqual(t, nil, err)
+	if len(hullVertexIndices(unnormalized)) >= len(hullVertexIndices(expected)) {
+		t.Errorf("expected the translated point cloud to lose vertices without normalization")
+	}
+
+	// An absolute epsilon is in the units of the input, regardless of normalization
+	scaled := make([]r3.Vector, len(pointCloud))
+	for i, v := range pointCloud {
+		scaled[i] = v.Mul(1000)
+	}
+	absolute, err := new(QuickHull).TryConvexHull(scaled, Options{IndexMode: OriginalIndices, Normalize: true, Epsilon: 1e-4, EpsilonMode: AbsoluteEpsilon})
+	assertEqual(t, nil, err)
+	assertEqual(t, hullVertexIndices(expected), hullVertexIndices(absolute))
+}
+
+// Returns the sorted indices of the vertices used by the triangles of the hull.
+func hullVertexIndices(hull ConvexHull) []int {
+	seen := make(map[int]bool)
+	var indices []int
+	for _, idx := range hull.Indices {
+		if !seen[idx] {
+			seen[idx] = true
+			indices = append(indices, idx)
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// The corners of an axis aligned cube centered at the origin.
+func cubePointCloud(halfExtent float64) []r3.Vector {
+	var pointCloud []r3.Vector
+	for _, x := range []float64{-halfExtent, halfExtent} {
+		for _, y := range []float64{-halfExtent, halfExtent} {
+			for _, z := range []float64{-halfExtent, halfExtent} {
+				pointCloud = append(pointCloud, r3.Vector{X: x, Y: y, Z: z})
+			}
+		}
+	}
+	return pointCloud
+}
+
+// Points on a unit sphere snapped to a coarse grid, which causes a failed horizon edge with epsilon = 1e-14.
+func horizonFailurePointCloud() []r3.Vector {
+	r := rand.New(rand.NewSource(204))
+
+	pointCloud := make([]r3.Vector, 500)
+	for i := range pointCloud {
+		theta, phi := r.Float64()*2*math.Pi, math.Acos(2*r.Float64()-1)
+		pointCloud[i] = r3.Vector{
+			X: math.Round(math.Sin(phi)*math.Cos(theta)*20) / 20,
+			Y: math.Round(math.Sin(phi)*math.Sin(theta)*20) / 20,
+			Z: math.Round(math.Cos(phi)*20) / 20,
+		}
+	}
+
+	return pointCloud
+}
+
+func TestParallelMatchesSerial(t *testing.T) {
+	pointCloud := randomPointCloud(50000)
+
+	serial, err := new(QuickHull).TryConvexHull(pointCloud, Options{})
+	assertEqual(t, nil, err)
+
+	parallel, err := new(QuickHull).TryConvexHull(pointCloud, Options{Workers: 4})
+	assertEqual(t, nil, err)
+
+	assertEqual(t, serial, parallel)
+}
+
+func TestCullInterior(t *testing.T) {
+	pointCloud := randomPointCloud(10000)
+
+	expected, err := new(QuickHull).TryConvexHull(pointCloud, Options{})
+	assertEqual(t, nil, err)
+
+	for _, mode := range []IndexMode{CompactIndices, OriginalIndices} {
+		hull, err := new(QuickHull).TryConvexHull(pointCloud, Options{CullInterior: true, IndexMode: mode})
+		assertEqual(t, nil, err)
+
+		vertices := make([]r3.Vector, 0, len(hull.Indices))
+		seen := make(map[int]bool)
+		for _, idx := range hull.Indices {
+			if !seen[idx] {
+				seen[idx] = true
+				vertices = append(vertices, hull.Vertices[idx])
+			}
+		}
+		assertElementsMatch(t, expected.Vertices, vertices, "culling must not change the hull")
+		assertClosedMesh(t, hull)
+
+		if hull.Diagnostics.PointsCulled < len(pointCloud)/2 {
+			t.Errorf("expected most points to be culled, only %d were", hull.Diagnostics.PointsCulled)
+		}
+	}
+
+	// Culling checks for cancellation too, after the 10 checks during validation
+	canceled, err := new(QuickHull).ConvexHullContext(&countdownContext{Context: context.Background(), n: 10}, randomPointCloud(10*cancellationCheckPoints), Options{CullInterior: true})
+	assertEqual(t, context.Canceled, err)
+	assertEqual(t, 0, len(canceled.Indices))
+
+	// Culling must keep the normalized coordinates, otherwise the bumps on the faces of the cube that are within epsilon become vertices
+	cube := cubePointCloud(100)
+	for _, p := range pointCloud[:1000] {
+		cube = append(cube, p.Mul(50))
+	}
+	cube = append(cube, r3.Vector{X: 100.01}, r3.Vector{Y: -100.01}, r3.Vector{Z: 100.01})
+	normalized, err := new(QuickHull).TryConvexHull(cube, Options{IndexMode: OriginalIndices, Normalize: true, Epsilon: 1e-3})
+	assertEqual(t, nil, err)
+	culled, err := new(QuickHull).TryConvexHull(cube, Options{IndexMode: OriginalIndices, Normalize: true, Epsilon: 1e-3, CullInterior: true})
+	assertEqual(t, nil, err)
+	assertEqual(t, hullVertexIndices(normalized), hullVertexIndices(culled))
+
+	// Planar point clouds can't be culled
+	hull, err := new(QuickHull).TryConvexHull([]r3.Vector{{X: 0, Y: 0, Z: 1}, {X: 0, Y: 10, Z: 1}, {X: 10, Y: 0, Z: 1}, {X: 10, Y: 10, Z: 1}, {X: 5, Y: 5, Z: 1}}, Options{CullInterior: true})
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, hull.Diagnostics.PointsCulled)
+	assertEqual(t, 4, len(hull.Vertices))
+}
+
+func TestClassify(t *testing.T) {
+	cube := []r3.Vector{
+		{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}, {X: 0, Y: 1, Z: 0}, {X: 1, Y: 1, Z: 0},
+		{X: 0, Y: 0, Z: 1}, {X: 1, Y: 0, Z: 1}, {X: 0, Y: 1, Z: 1}, {X: 1, Y: 1, Z: 1},
+	}
+
+	tests := []struct {
+		name       string
+		pointCloud []r3.Vector
+		expected   Dimension
+	}{
+		{"point", []r3.Vector{{X: 1, Y: 2, Z: 3}, {X: 1, Y: 2, Z: 3}}, DimensionPoint},
+		{"segment", []r3.Vector{{X: 0, Y: 0, Z: 0}, {X: 1, Y: 1, Z: 1}, {X: 2, Y: 2, Z: 2}, {X: 3, Y: 3, Z: 3}, {X: 4, Y: 4, Z: 4}}, DimensionSegment},
+		{"polygon", cube[:4], DimensionPolygon},
+		{"polytope", cube, DimensionPolytope},
+	}
+
+	for _, test := range tests {
+		c, err := Classify(test.pointCloud, 0)
+		assertEqual(t, nil, err)
+		assertEqual(t, test.expected, c.Dimension)
+		assertEqual(t, int(test.expected)+1, len(c.Indices))
+
+		hull, err := new(QuickHull).TryConvexHull(test.pointCloud, Options{})
+		assertEqual(t, nil, err)
+		assertEqual(t, test.expected, hull.Dimension)
+	}
+
+	_, err := Classify(nil, 0)
+	assertErrorKind(t, KindDegenerateInput, err)
+}
+
+func TestConvexHullDimension(t *testing.T) {
+	hull, err := new(QuickHull).TryConvexHull([]r3.Vector{{X: 1, Y: 2, Z: 3}, {X: 1, Y: 2, Z: 3}}, Options{})
+	assertEqual(t, nil, err)
+	assertEqual(t, DimensionPoint, hull.Dimension)
+	assertEqual(t, 0, len(hull.Indices))
+	assertEqual(t, []r3.Vector{{X: 1, Y: 2, Z: 3}}, hull.Vertices)
+	assertEqual(t, []int{0}, hull.Outline)
+
+	segment := []r3.Vector{{X: 2, Y: 2, Z: 2}, {X: 0, Y: 0, Z: 0}, {X: 1, Y: 1, Z: 1}, {X: 4, Y: 4, Z: 4}, {X: 3, Y: 3, Z: 3}}
+	hull, err = new(QuickHull).TryConvexHull(segment, Options{})
+	assertEqual(t, nil, err)
+	assertEqual(t, DimensionSegment, hull.Dimension)
+	assertEqual(t, 0, len(hull.Indices))
+	assertElementsMatch(t, []r3.Vector{{X: 0, Y: 0, Z: 0}, {X: 4, Y: 4, Z: 4}}, hull.Vertices)
+
+	mesh, err := new(QuickHull).TryConvexHullAsMesh(segment, Options{IndexMode: OriginalIndices})
+	assertEqual(t, nil, err)
+	assertEqual(t, DimensionSegment, mesh.Dimension)
+	assertEqual(t, 0, len(mesh.Faces))
+	sort.Ints(mesh.Outline)
+	assertEqual(t, []int{1, 3}, mesh.Outline)
+
+	// The legacy functions still output a degenerate tetrahedron
+	legacyMesh := new(QuickHull).ConvexHullAsMesh(segment, 0)
+	assertEqual(t, DimensionSegment, legacyMesh.Dimension)
+	assertEqual(t, 4, len(legacyMesh.Faces))
+
+	// Square with a point on an edge and one inside, the outline must only contain the corners in order
+	square := []r3.Vector{
+		{X: 0, Y: 0, Z: 1},
+		{X: 0, Y: 5, Z: 1},
+		{X: 0, Y: 10, Z: 1},
+		{X: 10, Y: 0, Z: 1},
+		{X: 10, Y: 10, Z: 1},
+		{X: 5, Y: 5, Z: 1},
+	}
+	for _, mode := range []IndexMode{CompactIndices, OriginalIndices} {
+		hull, err = new(QuickHull).TryConvexHull(square, Options{IndexMode: mode})
+		assertEqual(t, nil, err)
+		assertEqual(t, DimensionPolygon, hull.Dimension)
+		assertEqual(t, 4, len(hull.Outline))
+
+		var outline []r3.Vector
+		for i, idx := range hull.Outline {
+			outline = append(outline, hull.Vertices[idx])
+			next := hull.Vertices[hull.Outline[(i+1)%len(hull.Outline)]]
+			assertEqual(t, 10.0, hull.Vertices[idx].Distance(next))
+		}
+		assertElementsMatch(t, []r3.Vector{{X: 0, Y: 0, Z: 1}, {X: 0, Y: 10, Z: 1}, {X: 10, Y: 0, Z: 1}, {X: 10, Y: 10, Z: 1}}, outline)
+	}
+
+	hull, err = new(QuickHull).TryConvexHull(randomPointCloud(100), Options{})
+	assertEqual(t, nil, err)
+	assertEqual(t, DimensionPolytope, hull.Dimension)
+	assertEqual(t, 0, len(hull.Outline))
+}
+
+func TestConvexHullPolygon(t *testing.T) {
+	square := []r3.Vector{
+		{X: 0, Y: 0, Z: 1},
+		{X: 0, Y: 5, Z: 1},
+		{X: 0, Y: 10, Z: 1},
+		{X: 10, Y: 0, Z: 1},
+		{X: 10, Y: 10, Z: 1},
+		{X: 5, Y: 5, Z: 1},
+	}
+
+	signedArea := func(poly *Polygon) float64 {
+		var a float64
+		for i, p := range poly.Points {
+			a += p.Cross(poly.Points[(i+1)%len(poly.Points)])
+		}
+		return a / 2
+	}
+
+	for _, winding := range []Winding{CounterClockwise, Clockwise} {
+		hull, err := new(QuickHull).TryConvexHull(square, Options{Winding: winding})
+		assertEqual(t, nil, err)
+
+		poly := hull.Polygon
+		assertEqual(t, r3.Vector{X: 0, Y: 0, Z: 1}, poly.Normal)
+		assertEqual(t, 1.0, poly.Offset)
+		assertEqual(t, r3.Vector{X: 1, Y: 0, Z: 0}, poly.U)
+		assertEqual(t, r3.Vector{X: 0, Y: 1, Z: 0}, poly.V)
+		assertEqual(t, hull.Outline, poly.Indices)
+		assertEqual(t, 4, len(poly.Points))
+		for i, idx := range poly.Indices {
+			assertEqual(t, hull.Vertices[idx], poly.Vertices[i])
+			assertEqual(t, poly.Vertices[i].X, poly.Points[i].X)
+			assertEqual(t, poly.Vertices[i].Y, poly.Points[i].Y)
+		}
+
+		expectedArea := 100.0
+		if winding == Clockwise {
+			expectedArea = -100
+		}
+		assertEqual(t, expectedArea, signedArea(poly))
+	}
+
+	// Tilted triangle, the normal must be canonical regardless of the order of the points
+	triangle := []r3.Vector{{X: 0, Y: 0, Z: 0}, {X: 0, Y: 1, Z: 1}, {X: 1, Y: 0, Z: 0}}
+	for _, pointCloud := range [][]r3.Vector{triangle, {triangle[0], triangle[2], triangle[1]}} {
+		hull, err := new(QuickHull).TryConvexHull(pointCloud, Options{})
+		assertEqual(t, nil, err)
+		assertEqual(t, DimensionPolygon, hull.Dimension)
+
+		poly := hull.Polygon
+		if poly.Normal.Sub(r3.Vector{X: 0, Y: -1, Z: 1}.Normalize()).Norm() > 1e-12 {
+			t.Errorf("unexpected normal %v", poly.Normal)
+		}
+		if math.Abs(poly.U.Cross(poly.V).Sub(poly.Normal).Norm()) > 1e-12 {
+			t.Errorf("basis %v, %v doesn't match normal %v", poly.U, poly.V, poly.Normal)
+		}
+		if a := signedArea(poly); math.Abs(a-math.Sqrt2/2) > 1e-12 {
+			t.Errorf("expected counter-clockwise polygon with area %v, got %v", math.Sqrt2/2, a)
+		}
+	}
+
+	hull, err := new(QuickHull).TryConvexHull(randomPointCloud(100), Options{})
+	assertEqual(t, nil, err)
+	assertEqual(t, (*Polygon)(nil), hull.Polygon)
+}
+
+func BenchmarkConvexHull(b *testing.B) {
+	pointCloud := randomPointCloud(1000000)
+
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = new(QuickHull).TryConvexHull(pointCloud, Options{Workers: workers})
+			}
+		})
+	}
+
+	b.Run("cull", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = new(QuickHull).TryConvexHull(pointCloud, Options{CullInterior: true})
+		}
+	})
+}
+
+// Every edge of a closed triangle mesh must be shared by exactly two triangles, once in each direction.
+func assertClosedMesh(t *testing.T, hull ConvexHull) {
+	t.Helper()
+
+	edges := make(map[[2]int]int)
+	for i := 0; i < len(hull.Indices); i += 3 {
+		for j := 0; j < 3; j++ {
+			edges[[2]int{hull.Indices[i+j], hull.Indices[i+(j+1)%3]}]++
+		}
+	}
+
+	for e, n := range edges {
+		if n != 1 || edges[[2]int{e[1], e[0]}] != 1 {
+			t.Errorf("edge %v is not shared by exactly two triangles", e)
+		}
+	}
+}
+
+func randomPointCloud(n int) []r3.Vector {
+	pointCloud := make([]r3.Vector, n)
+	for i := range pointCloud {
+		pointCloud[i] = r3.Vector{
+			X: randF64(-1, 1),
+			Y: randF64(-1, 1),
+			Z: randF64(-1, 1),
+		}
+	}
+	return pointCloud
+}
+
+func assertErrorKind(t *testing.T, expected ErrorKind, err error) {
+	t.Helper()
+
+	e, ok := err.(*Error)
+	if !ok {
+		t.Errorf("expected *Error of kind %v, got %v", expected, err)
+		return
+	}
+
+	assertEqual(t, expected, e.Kind)
+}
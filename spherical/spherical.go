@@ -0,0 +1,138 @@
+// Package spherical computes Delaunay triangulations and Voronoi diagrams of points on the unit sphere.
+//
+// The convex hull of points on a sphere is their spherical Delaunay triangulation, and the outward normals of the hull
+// faces are the Voronoi vertices (the centers of the empty circles through the corners of each face).
+package spherical
+
+import (
+	"fmt"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+// Cell is the Voronoi cell of a point: the region of the sphere that is closer to the point than to any other point.
+type Cell struct {
+	Loop *s2.Loop // Boundary of the cell, nil if the point (nearly) coincides with another point, which gets the cell
+	Area float64  // Area of the cell in steradians, same as Loop.Area()
+}
+
+// Triangulate computes the spherical Delaunay triangulation of the points.
+// The returned triangles refer to the indices of the points and are counter-clockwise when viewed from outside of the sphere.
+// If the points don't surround the center of the sphere, the triangles that would contain the empty region are omitted.
+// If epsilon is <= 0 a default value will be used.
+// Returns an error of kind quickhull.KindDegenerateInput if the points lie on a common plane (e.g. a great circle).
+func Triangulate(points []s2.Point, epsilon float64) ([][3]int, error) {
+	if epsilon <= 0 {
+		epsilon = quickhull.DefaultEpsilon
+	}
+
+	// With Clockwise winding the right-hand normals point out of the hull
+	hull, err := new(quickhull.QuickHull).TryConvexHull(vectors(points), quickhull.Options{
+		Epsilon:        epsilon,
+		Winding:        quickhull.Clockwise,
+		IndexMode:      quickhull.OriginalIndices,
+		ComputeNormals: true,
+		Robust:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if hull.Dimension < quickhull.DimensionPolytope {
+		return nil, degenerate(hull.Dimension)
+	}
+
+	var triangles [][3]int
+	for i, n := range hull.Normals {
+		tri := [3]int{hull.Indices[3*i], hull.Indices[3*i+1], hull.Indices[3*i+2]}
+		// The plane of the face must separate it from the center of the sphere
+		if n.Dot(hull.Vertices[tri[0]]) > epsilon {
+			triangles = append(triangles, tri)
+		}
+	}
+
+	return triangles, nil
+}
+
+// Voronoi computes the spherical Voronoi diagram of the points, cells[i] is the cell of points[i].
+// If epsilon is <= 0 a default value will be used.
+// Returns an error of kind quickhull.KindDegenerateInput if the points lie on a common plane (e.g. a great circle).
+func Voronoi(points []s2.Point, epsilon float64) ([]Cell, error) {
+	if epsilon <= 0 {
+		epsilon = quickhull.DefaultEpsilon
+	}
+
+	mesh, err := new(quickhull.QuickHull).TryConvexHullAsMesh(vectors(points), quickhull.Options{
+		Epsilon:        epsilon,
+		IndexMode:      quickhull.OriginalIndices,
+		ComputeNormals: true,
+		Robust:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if mesh.Dimension < quickhull.DimensionPolytope {
+		return nil, degenerate(mesh.Dimension)
+	}
+
+	// Any half edge ending at the point
+	incoming := make([]int, len(points))
+	for i := range incoming {
+		incoming[i] = -1
+	}
+	for i, he := range mesh.HalfEdges {
+		incoming[he.EndVertex] = i
+	}
+
+	cells := make([]Cell, len(points))
+	for i, start := range incoming {
+		if start < 0 {
+			continue
+		}
+
+		// Walk around the point through the faces sharing it, skipping faces with (nearly) the same normal
+		var vertices []s2.Point
+		for h := start; ; {
+			v := s2.Point{Vector: mesh.Normals[mesh.HalfEdges[h].Face]}
+			if len(vertices) == 0 || vertices[len(vertices)-1].Sub(v.Vector).Norm() > epsilon {
+				vertices = append(vertices, v)
+			}
+			h = mesh.HalfEdges[mesh.HalfEdges[h].Next].Opp
+			if h == start || len(vertices) > len(mesh.Faces) {
+				break
+			}
+		}
+		for len(vertices) > 1 && vertices[0].Sub(vertices[len(vertices)-1].Vector).Norm() <= epsilon {
+			vertices = vertices[:len(vertices)-1]
+		}
+		if len(vertices) < 3 {
+			continue
+		}
+
+		// Loops are counter-clockwise around their interior
+		if s2.RobustSign(vertices[0], vertices[1], points[i]) == s2.Clockwise {
+			for a, b := 0, len(vertices)-1; a < b; a, b = a+1, b-1 {
+				vertices[a], vertices[b] = vertices[b], vertices[a]
+			}
+		}
+
+		loop := s2.LoopFromPoints(vertices)
+		cells[i] = Cell{Loop: loop, Area: loop.Area()}
+	}
+
+	return cells, nil
+}
+
+func vectors(points []s2.Point) []r3.Vector {
+	vs := make([]r3.Vector, len(points))
+	for i, p := range points {
+		vs[i] = p.Vector
+	}
+	return vs
+}
+
+func degenerate(dim quickhull.Dimension) error {
+	return &quickhull.Error{Kind: quickhull.KindDegenerateInput, Msg: fmt.Sprintf("points span only a %v", dim)}
+}
@@ -0,0 +1,157 @@
+package spherical
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/golang/geo/s2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func assertAlmostEqual(t *testing.T, expected, actual float64) {
+	t.Helper()
+
+	if math.Abs(expected-actual) > 1e-9 {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func randomPoints(r *rand.Rand, n int) []s2.Point {
+	points := make([]s2.Point, n)
+	for i := range points {
+		points[i] = s2.PointFromCoords(r.NormFloat64(), r.NormFloat64(), r.NormFloat64())
+	}
+	return points
+}
+
+// Checks that all triangles are counter-clockwise and that no point is inside the circumcircle of any triangle.
+func assertDelaunay(t *testing.T, points []s2.Point, triangles [][3]int) {
+	t.Helper()
+
+	for _, tri := range triangles {
+		a, b, c := points[tri[0]], points[tri[1]], points[tri[2]]
+		n := b.Sub(a.Vector).Cross(c.Sub(a.Vector))
+		if n.Dot(a.Vector) <= 0 {
+			t.Fatalf("triangle %v isn't counter-clockwise", tri)
+		}
+		for i, p := range points {
+			if n.Dot(p.Sub(a.Vector)) > 1e-9 {
+				t.Fatalf("point %d is inside the circumcircle of %v", i, tri)
+			}
+		}
+	}
+}
+
+func TestOctahedron(t *testing.T) {
+	points := []s2.Point{
+		s2.PointFromCoords(1, 0, 0), s2.PointFromCoords(-1, 0, 0),
+		s2.PointFromCoords(0, 1, 0), s2.PointFromCoords(0, -1, 0),
+		s2.PointFromCoords(0, 0, 1), s2.PointFromCoords(0, 0, -1),
+	}
+
+	triangles, err := Triangulate(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 8, len(triangles))
+	assertDelaunay(t, points, triangles)
+
+	cells, err := Voronoi(points, 0)
+	assertEqual(t, nil, err)
+	for i, c := range cells {
+		assertEqual(t, 4, c.Loop.NumVertices())
+		assertAlmostEqual(t, 4*math.Pi/6, c.Area)
+		assertEqual(t, true, c.Loop.ContainsPoint(points[i]))
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := randomPoints(r, 500)
+
+	triangles, err := Triangulate(points, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 2*len(points)-4, len(triangles))
+	assertDelaunay(t, points, triangles)
+
+	cells, err := Voronoi(points, 0)
+	assertEqual(t, nil, err)
+
+	var total float64
+	for _, c := range cells {
+		total += c.Area
+	}
+	assertAlmostEqual(t, 4*math.Pi, total)
+
+	// Every point is in the cell of its nearest point
+	for _, p := range randomPoints(r, 1000) {
+		nearest := 0
+		for i, q := range points {
+			if q.Distance(p) < points[nearest].Distance(p) {
+				nearest = i
+			}
+		}
+		if !cells[nearest].Loop.ContainsPoint(p) {
+			t.Fatalf("point %v isn't in the cell of its nearest point %v", p, points[nearest])
+		}
+	}
+}
+
+func TestHemisphere(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var points []s2.Point
+	for _, p := range randomPoints(r, 400) {
+		if p.Z > 0.2 {
+			points = append(points, p)
+		}
+	}
+
+	triangles, err := Triangulate(points, 0)
+	assertEqual(t, nil, err)
+	assertDelaunay(t, points, triangles)
+
+	// The triangles cover the convex hull of the points (on the sphere), but not the south pole
+	var area float64
+	southPole := s2.PointFromCoords(0, 0, -1)
+	for _, tri := range triangles {
+		loop := s2.LoopFromPoints([]s2.Point{points[tri[0]], points[tri[1]], points[tri[2]]})
+		if loop.ContainsPoint(southPole) {
+			t.Fatalf("triangle %v contains the south pole", tri)
+		}
+		area += loop.Area()
+	}
+	if area >= 2*math.Pi {
+		t.Errorf("triangles cover %v, more than the hemisphere", area)
+	}
+
+	cells, err := Voronoi(points, 0)
+	assertEqual(t, nil, err)
+	var total float64
+	for _, c := range cells {
+		total += c.Area
+	}
+	assertAlmostEqual(t, 4*math.Pi, total)
+}
+
+func TestDegenerate(t *testing.T) {
+	var equator []s2.Point
+	for i := 0; i < 10; i++ {
+		a := float64(i) * 2 * math.Pi / 10
+		equator = append(equator, s2.PointFromCoords(math.Cos(a), math.Sin(a), 0))
+	}
+
+	_, err := Triangulate(equator, 0)
+	assertEqual(t, quickhull.KindDegenerateInput, err.(*quickhull.Error).Kind)
+
+	_, err = Voronoi(equator, 0)
+	assertEqual(t, quickhull.KindDegenerateInput, err.(*quickhull.Error).Kind)
+}
@@ -0,0 +1,97 @@
+package quickhull
+
+import (
+	"github.com/golang/geo/r3"
+)
+
+// Support returns the index of the vertex of the hull that is farthest in the given direction, -1 for empty hulls.
+// The search climbs from vertex to neighboring vertex, which is much faster than checking all vertices for large hulls.
+// Hulls built by the caller are searched by checking all vertices of the triangles instead.
+func (hull ConvexHull) Support(direction r3.Vector) int {
+	if hull.Dimension < DimensionPolytope {
+		return outlineSupport(hull.Vertices, hull.Outline, direction)
+	}
+	if hull.derived == nil {
+		// There is nowhere to keep the adjacency, see ClosestPoint
+		return outlineSupport(hull.Vertices, hull.Indices, direction)
+	}
+	return support(hull, direction)
+}
+
+// SupportPoint returns the vertex of the hull that is farthest in the given direction, see Support.
+// The hull must not be empty. This makes ConvexHull a SupportMapping.
+func (hull ConvexHull) SupportPoint(direction r3.Vector) r3.Vector {
+	return hull.Vertices[hull.Support(direction)]
+}
+
+// Support returns the index of the vertex of the mesh that is farthest in the given direction, see ConvexHull.Support.
+func (mesh HalfEdgeMesh) Support(direction r3.Vector) int {
+	if mesh.Dimension < DimensionPolytope {
+		return outlineSupport(mesh.Vertices, mesh.Outline, direction)
+	}
+	return support(mesh, direction)
+}
+
+// SupportPoint returns the vertex of the mesh that is farthest in the given direction, see ConvexHull.Support.
+func (mesh HalfEdgeMesh) SupportPoint(direction r3.Vector) r3.Vector {
+	return mesh.Vertices[mesh.Support(direction)]
+}
+
+// Hill-climbs over the vertices of the mesh. A vertex is identified by a triangle and a corner of it, so the neighbors
+// can be visited by walking around the vertex from triangle to triangle.
+func support(m triangleMesh, direction r3.Vector) int {
+	if m.triangleCount() == 0 {
+		return -1
+	}
+
+	tri, corner := 0, 0
+	v := m.triangle(tri)[corner]
+	best := m.vertex(v).Dot(direction)
+	for {
+		nextTri, nextCorner := -1, -1
+
+		// The neighbors are the corners following v in the triangles around it
+		i, j := tri, corner
+		for steps := 0; steps < m.triangleCount(); steps++ {
+			w := m.triangle(i)[(j+1)%3]
+			if d := m.vertex(w).Dot(direction); d > best {
+				best, nextTri, nextCorner = d, i, (j+1)%3
+			}
+
+			// Continue in the triangle across the edge from the previous corner to v
+			i = m.neighbor(i, (j+2)%3)
+			if i < 0 || i == tri {
+				break
+			}
+			j = cornerOf(m.triangle(i), v)
+		}
+
+		if nextTri < 0 {
+			return v
+		}
+		tri, corner = nextTri, nextCorner
+		v = m.triangle(tri)[corner]
+	}
+}
+
+// Returns the index of vertex v in the triangle, -1 if it's not a corner.
+func cornerOf(triangle [3]int, v int) int {
+	for j, w := range triangle {
+		if w == v {
+			return j
+		}
+	}
+	return -1
+}
+
+// Returns the vertex of the outline (or any list of vertex indices) that is farthest in the given direction.
+func outlineSupport(vertices []r3.Vector, outline []int, direction r3.Vector) int {
+	best := -1
+	var bestD float64
+	for _, v := range outline {
+		if d := vertices[v].Dot(direction); best < 0 || d > bestD {
+			best, bestD = v, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,367 @@
+package quickhull
+
+import (
+	"context"
+	"sort"
+
+	"github.com/golang/geo/r3"
+)
+
+// Tetrahedralization is a Delaunay tetrahedralization of a point cloud: no point is inside the circumsphere of any tetrahedron.
+type Tetrahedralization struct {
+	// Indices of the corners into the point cloud.
+	// The right-hand normal of the triangle of the first three corners points towards the fourth corner.
+	Tetrahedra [][4]int
+	// Neighbors[i][j] is the index of the tetrahedron sharing the face opposite of Tetrahedra[i][j], -1 on the convex hull.
+	Neighbors [][4]int
+	// Indices of the points that weren't inserted because they coincide (within epsilon) with another point.
+	Skipped []int
+}
+
+// Marks the vertex at infinity of the ghost tetrahedra outside of the convex hull.
+const infiniteVertex = -1
+
+// Tetrahedron of the triangulation under construction.
+// Ghost tetrahedra connect a face of the convex hull with the infinite vertex, which is always the fourth vertex.
+// All tetrahedra are positively oriented, for ghosts this means that the interior of the hull is on the negative side of the face.
+type tetrahedron struct {
+	v        [4]int
+	n        [4]int // n[i] is the tetrahedron opposite of v[i]
+	dead     bool
+	visited  int // Insertion during which the tetrahedron was tested for conflicts
+	conflict bool
+	cavity   int // Insertion during which the tetrahedron was added to the cavity
+}
+
+func (t *tetrahedron) isGhost() bool {
+	return t.v[3] == infiniteVertex
+}
+
+type tetrahedralizer struct {
+	points   []r3.Vector
+	epsilon  float64
+	interior r3.Vector // A point strictly inside the convex hull of the inserted points
+	tets     []tetrahedron
+	free     []int // Dead tetrahedra whose slots can be reused
+	last     int   // Tetrahedron created most recently, point location starts there
+
+	cavity  []int
+	faces   map[[3]int]newFace
+	stamp   int
+	skipped []int
+}
+
+// Face of a new tetrahedron that still needs to be connected with its neighbor.
+type newFace struct {
+	tet   int
+	index int
+}
+
+// Tetrahedralize computes the Delaunay tetrahedralization of the point cloud using the Bowyer–Watson algorithm.
+// Epsilon is interpreted like in ConvexHull: relative to the scale of the point cloud, if <= 0 a default value will be used.
+// Points that are within epsilon of an already inserted point are skipped. The predicates are exact and evaluated on the
+// input coordinates, so (nearly) cospherical points like grids are no problem.
+// Returns an error of kind KindDegenerateInput if the point cloud doesn't span a volume, see Classify.
+func Tetrahedralize(pointCloud []r3.Vector, epsilon float64) (res Tetrahedralization, err error) {
+	defer func() {
+		if e := errorFromPanic(recover()); e != nil {
+			res, err = Tetrahedralization{}, e
+		}
+	}()
+
+	var qh QuickHull
+	err = qh.setup(context.Background(), pointCloud, Options{Epsilon: epsilon, Normalize: true})
+	if err != nil {
+		return Tetrahedralization{}, err
+	}
+
+	dim, spanning := qh.spanningPoints()
+	if dim < DimensionPolytope {
+		return Tetrahedralization{}, newError(KindDegenerateInput, "point cloud spans only a %v", dim)
+	}
+
+	// The epsilon refers to the normalized points in vertexData, the predicates must see the original ones though
+	_, unit := normalization(boundingBox(pointCloud, qh.extremeValueIndices))
+	t := tetrahedralizer{
+		points:  pointCloud,
+		epsilon: qh.epsilon * unit,
+		faces:   make(map[[3]int]newFace),
+	}
+	t.createInitialTetrahedron(spanning)
+
+	for i := range t.points {
+		if i != spanning[0] && i != spanning[1] && i != spanning[2] && i != spanning[3] {
+			t.insert(i)
+		}
+	}
+
+	return t.result(), nil
+}
+
+// Creates a tetrahedron of the spanning points, surrounded by four ghosts.
+func (t *tetrahedralizer) createInitialTetrahedron(spanning [4]int) {
+	v := spanning
+	if t.orientation(v) < 0 {
+		v[0], v[1] = v[1], v[0]
+	}
+	for _, i := range v {
+		t.interior = t.interior.Add(t.points[i].Mul(0.25))
+	}
+
+	// Ghost i+1 is opposite of v[i]
+	t.tets = append(t.tets, tetrahedron{v: v, n: [4]int{1, 2, 3, 4}})
+	for i := range v {
+		ghost := tetrahedron{v: faceWith(v, i, infiniteVertex)}
+		t.orientGhost(&ghost)
+		// Across the face opposite of a corner is the ghost that is opposite of the same corner in the first tetrahedron
+		for j := 0; j < 3; j++ {
+			for k := range v {
+				if v[k] == ghost.v[j] {
+					ghost.n[j] = 1 + k
+				}
+			}
+		}
+		ghost.n[3] = 0
+		t.tets = append(t.tets, ghost)
+	}
+}
+
+// Returns the index in v of the vertex that isn't part of the face in the first three elements of face.
+func indexNotInFace(v [4]int, face [4]int) int {
+	for i, x := range v {
+		found := false
+		for _, y := range face[:3] {
+			found = found || x == y
+		}
+		if !found {
+			return i
+		}
+	}
+	panic(newError(KindInternal, "no vertex left"))
+}
+
+// Returns the face opposite of v[i] with p as fourth vertex.
+func faceWith(v [4]int, i int, p int) [4]int {
+	face := [4]int{}
+	k := 0
+	for j, x := range v {
+		if j != i {
+			face[k] = x
+			k++
+		}
+	}
+	face[3] = p
+	return face
+}
+
+func (t *tetrahedralizer) orientation(v [4]int) int {
+	return orientation(t.points[v[0]], t.points[v[1]], t.points[v[2]], t.points[v[3]])
+}
+
+// Swaps the first two vertices of the ghost if the interior isn't on the negative side of its face.
+func (t *tetrahedralizer) orientGhost(ghost *tetrahedron) {
+	if orientation(t.points[ghost.v[0]], t.points[ghost.v[1]], t.points[ghost.v[2]], t.interior) > 0 {
+		ghost.v[0], ghost.v[1] = ghost.v[1], ghost.v[0]
+	}
+}
+
+// Reports whether p is inside the circumsphere of the tetrahedron.
+// Points are inside the circumsphere of a ghost if they are beyond its face, or on the plane of the face and inside the
+// circumsphere of the tetrahedron on the other side of the face (i.e. inside the circumcircle of the face).
+func (t *tetrahedralizer) inConflict(ti int, p int) bool {
+	tet := &t.tets[ti]
+	if tet.visited == t.stamp {
+		return tet.conflict
+	}
+	tet.visited = t.stamp
+
+	pp := t.points[p]
+	if !tet.isGhost() {
+		tet.conflict = inSphere(t.points[tet.v[0]], t.points[tet.v[1]], t.points[tet.v[2]], t.points[tet.v[3]], pp) > 0
+		return tet.conflict
+	}
+
+	switch orientation(t.points[tet.v[0]], t.points[tet.v[1]], t.points[tet.v[2]], pp) {
+	case 1:
+		tet.conflict = true
+	case 0:
+		inner := &t.tets[tet.n[3]]
+		tet.conflict = inSphere(t.points[inner.v[0]], t.points[inner.v[1]], t.points[inner.v[2]], t.points[inner.v[3]], pp) > 0
+	default:
+		tet.conflict = false
+	}
+	return tet.conflict
+}
+
+// Finds a tetrahedron containing p (or a ghost whose face p is beyond) by walking from the last created tetrahedron.
+func (t *tetrahedralizer) locate(p int) int {
+	ti := t.last
+	for step := 0; step < len(t.tets); step++ {
+		tet := &t.tets[ti]
+		if tet.isGhost() {
+			if orientation(t.points[tet.v[0]], t.points[tet.v[1]], t.points[tet.v[2]], t.points[p]) > 0 {
+				return ti
+			}
+			ti = tet.n[3]
+			continue
+		}
+
+		moved := false
+		for k := 0; k < 4; k++ {
+			// Vary the order of the faces to avoid walking in circles
+			i := (k + step) % 4
+			v := tet.v
+			v[i] = p
+			if t.orientation(v) < 0 {
+				ti = tet.n[i]
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			return ti
+		}
+	}
+
+	// Fall back to a linear search, the walk should always succeed for Delaunay triangulations though
+	for ti := range t.tets {
+		if !t.tets[ti].dead && t.inConflict(ti, p) {
+			return ti
+		}
+	}
+	return t.last
+}
+
+// Inserts the point: removes all tetrahedra in conflict with it and connects the boundary of that cavity with the point.
+func (t *tetrahedralizer) insert(p int) {
+	t.stamp++
+
+	start := t.locate(p)
+	if !t.inConflict(start, p) {
+		// Only points coinciding with a vertex aren't in conflict with the tetrahedron containing them
+		t.skipped = append(t.skipped, p)
+		return
+	}
+
+	t.cavity = append(t.cavity[:0], start)
+	t.tets[start].cavity = t.stamp
+	for k := 0; k < len(t.cavity); k++ {
+		for _, ni := range t.tets[t.cavity[k]].n {
+			if t.tets[ni].cavity != t.stamp && t.inConflict(ni, p) {
+				t.tets[ni].cavity = t.stamp
+				t.cavity = append(t.cavity, ni)
+			}
+		}
+	}
+
+	// The nearest vertex is connected to p in the new triangulation, so it's a corner of the cavity
+	for _, ti := range t.cavity {
+		for _, v := range t.tets[ti].v {
+			if v != infiniteVertex && t.points[v].Sub(t.points[p]).Norm() <= t.epsilon {
+				t.skipped = append(t.skipped, p)
+				return
+			}
+		}
+	}
+
+	for k := range t.faces {
+		delete(t.faces, k)
+	}
+
+	for _, ci := range t.cavity {
+		for i := 0; i < 4; i++ {
+			outer := t.tets[ci].n[i]
+			if t.tets[outer].cavity == t.stamp {
+				continue
+			}
+
+			// Boundary face of the cavity, connect it with p
+			var tet tetrahedron
+			if t.tets[ci].isGhost() && i != 3 {
+				face := faceWith(t.tets[ci].v, i, infiniteVertex)
+				tet.v = [4]int{face[0], face[1], p, infiniteVertex}
+				t.orientGhost(&tet)
+			} else {
+				tet.v = faceWith(t.tets[ci].v, i, p)
+				if t.orientation(tet.v) < 0 {
+					tet.v[0], tet.v[1] = tet.v[1], tet.v[0]
+				}
+			}
+
+			ti := t.add(tet)
+			t.link(ti, p, outer)
+			t.last = ti
+		}
+	}
+
+	for _, ci := range t.cavity {
+		t.tets[ci].dead = true
+	}
+	t.free = append(t.free, t.cavity...)
+}
+
+// Stores the tetrahedron in the slot of a dead one if there is any and returns its index.
+func (t *tetrahedralizer) add(tet tetrahedron) int {
+	if n := len(t.free); n > 0 {
+		ti := t.free[n-1]
+		t.free = t.free[:n-1]
+		t.tets[ti] = tet
+		return ti
+	}
+	t.tets = append(t.tets, tet)
+	return len(t.tets) - 1
+}
+
+// Connects the new tetrahedron ti with the tetrahedron outside of the cavity and the other new tetrahedra.
+func (t *tetrahedralizer) link(ti int, p int, outer int) {
+	tet := &t.tets[ti]
+	for i, v := range tet.v {
+		if v == p {
+			tet.n[i] = outer
+			o := &t.tets[outer]
+			o.n[indexNotInFace(o.v, faceWith(tet.v, i, infiniteVertex))] = ti
+			continue
+		}
+
+		key := faceWith(tet.v, i, infiniteVertex)
+		sortedKey := [3]int{key[0], key[1], key[2]}
+		sort.Ints(sortedKey[:])
+		if other, found := t.faces[sortedKey]; found {
+			delete(t.faces, sortedKey)
+			tet.n[i] = other.tet
+			t.tets[other.tet].n[other.index] = ti
+		} else {
+			t.faces[sortedKey] = newFace{tet: ti, index: i}
+		}
+	}
+}
+
+// Returns the finite tetrahedra with neighbor indices mapped accordingly.
+func (t *tetrahedralizer) result() Tetrahedralization {
+	var res Tetrahedralization
+
+	mapping := make([]int, len(t.tets))
+	for i, tet := range t.tets {
+		mapping[i] = -1
+		if !tet.dead && !tet.isGhost() {
+			mapping[i] = len(res.Tetrahedra)
+			res.Tetrahedra = append(res.Tetrahedra, tet.v)
+		}
+	}
+
+	for i, tet := range t.tets {
+		if mapping[i] < 0 {
+			continue
+		}
+		var n [4]int
+		for j, ni := range tet.n {
+			n[j] = mapping[ni]
+		}
+		res.Neighbors = append(res.Neighbors, n)
+	}
+
+	res.Skipped = t.skipped
+	sort.Ints(res.Skipped)
+
+	return res
+}
@@ -0,0 +1,191 @@
+package quickhull
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestTetrahedralizeRandom(t *testing.T) {
+	pointCloud := randomPointCloud(300)
+
+	tet, err := Tetrahedralize(pointCloud, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, len(tet.Skipped))
+	assertTetrahedralization(t, pointCloud, tet)
+
+	// Empty circumsphere property
+	for i, v := range tet.Tetrahedra {
+		for j, p := range pointCloud {
+			if inSphere(pointCloud[v[0]], pointCloud[v[1]], pointCloud[v[2]], pointCloud[v[3]], p) > 0 {
+				t.Fatalf("point %d is inside the circumsphere of tetrahedron %d", j, i)
+			}
+		}
+	}
+
+	// Every point is a vertex
+	used := make(map[int]bool)
+	for _, v := range tet.Tetrahedra {
+		for _, idx := range v {
+			used[idx] = true
+		}
+	}
+	assertEqual(t, len(pointCloud), len(used))
+
+	// The tetrahedra fill the convex hull
+	hull := convexHull(pointCloud)
+	assertAlmostEqual(t, hull.Volume(), tetrahedraVolume(pointCloud, tet), 1e-9)
+	hullFaces := 0
+	for _, n := range tet.Neighbors {
+		for _, x := range n {
+			if x < 0 {
+				hullFaces++
+			}
+		}
+	}
+	assertEqual(t, len(hull.Indices)/3, hullFaces)
+}
+
+func TestTetrahedralizeGrid(t *testing.T) {
+	var pointCloud []r3.Vector
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			for z := 0; z < 4; z++ {
+				pointCloud = append(pointCloud, r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)})
+			}
+		}
+	}
+
+	tet, err := Tetrahedralize(pointCloud, 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, len(tet.Skipped))
+	assertTetrahedralization(t, pointCloud, tet)
+	assertAlmostEqual(t, 27.0, tetrahedraVolume(pointCloud, tet), 1e-9)
+}
+
+func TestTetrahedralizeNearlyCospherical(t *testing.T) {
+	// A grid away from the origin jittered by far less than the rounding errors of normalizing its coordinates,
+	// the predicates must see the input coordinates to get the empty circumsphere property right
+	r := rand.New(rand.NewSource(12))
+	var pointCloud []r3.Vector
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			for z := 0; z < 4; z++ {
+				pointCloud = append(pointCloud, r3.Vector{
+					X: 1000 + float64(x) + 1e-13*r.Float64(),
+					Y: 3 + float64(y) + 1e-13*r.Float64(),
+					Z: float64(z) + 1e-13*r.Float64(),
+				})
+			}
+		}
+	}
+
+	tet, err := Tetrahedralize(pointCloud, 1e-15)
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, len(tet.Skipped))
+	assertTetrahedralization(t, pointCloud, tet)
+
+	for i, v := range tet.Tetrahedra {
+		for j, p := range pointCloud {
+			if inSphere(pointCloud[v[0]], pointCloud[v[1]], pointCloud[v[2]], pointCloud[v[3]], p) > 0 {
+				t.Fatalf("point %d is inside the circumsphere of tetrahedron %d", j, i)
+			}
+		}
+	}
+}
+
+func TestTetrahedralizeDuplicates(t *testing.T) {
+	pointCloud := randomPointCloud(50)
+	pointCloud = append(pointCloud, pointCloud[3], pointCloud[7].Add(r3.Vector{X: 1e-12}))
+
+	tet, err := Tetrahedralize(pointCloud, 0)
+	assertEqual(t, nil, err)
+	assertTetrahedralization(t, pointCloud, tet)
+
+	// Which point of a pair is skipped depends on the insertion order, but exactly one of them must be
+	assertEqual(t, 2, len(tet.Skipped))
+	skipped := make(map[int]bool)
+	for _, idx := range tet.Skipped {
+		skipped[idx] = true
+	}
+	for _, pair := range [][2]int{{3, 50}, {7, 51}} {
+		if skipped[pair[0]] == skipped[pair[1]] {
+			t.Errorf("expected exactly one of the points %v to be skipped, got %v", pair, tet.Skipped)
+		}
+	}
+	for _, v := range tet.Tetrahedra {
+		for _, idx := range v {
+			if skipped[idx] {
+				t.Errorf("skipped point %d is a vertex", idx)
+			}
+		}
+	}
+}
+
+func TestTetrahedralizeDegenerate(t *testing.T) {
+	square := []r3.Vector{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0.5, Y: 0.5}}
+
+	_, err := Tetrahedralize(square, 0)
+	assertErrorKind(t, KindDegenerateInput, err)
+
+	_, err = Tetrahedralize(nil, 0)
+	assertErrorKind(t, KindDegenerateInput, err)
+}
+
+// Checks orientation and adjacency of the tetrahedra.
+func assertTetrahedralization(t *testing.T, pointCloud []r3.Vector, tet Tetrahedralization) {
+	t.Helper()
+
+	assertEqual(t, len(tet.Tetrahedra), len(tet.Neighbors))
+
+	hullFaces := 0
+	for i, v := range tet.Tetrahedra {
+		if orientation(pointCloud[v[0]], pointCloud[v[1]], pointCloud[v[2]], pointCloud[v[3]]) <= 0 {
+			t.Fatalf("tetrahedron %d is not positively oriented", i)
+		}
+
+		for j, n := range tet.Neighbors[i] {
+			if n < 0 {
+				hullFaces++
+				continue
+			}
+
+			// The neighbor shares the face opposite of v[j] and points back
+			shared := 0
+			back := false
+			for k, w := range tet.Tetrahedra[n] {
+				for l, x := range v {
+					if l != j && x == w {
+						shared++
+					}
+				}
+				back = back || tet.Neighbors[n][k] == i
+			}
+			if shared != 3 || !back {
+				t.Fatalf("tetrahedra %d and %d are not adjacent", i, n)
+			}
+		}
+	}
+
+	if hullFaces == 0 {
+		t.Fatal("tetrahedralization has no boundary")
+	}
+}
+
+func tetrahedraVolume(pointCloud []r3.Vector, tet Tetrahedralization) float64 {
+	var volumes []float64
+	for _, v := range tet.Tetrahedra {
+		a, b, c, d := pointCloud[v[0]], pointCloud[v[1]], pointCloud[v[2]], pointCloud[v[3]]
+		volumes = append(volumes, triangleNormal(a, b, c).Dot(d.Sub(c))/6)
+	}
+
+	// Sum the small ones first
+	sort.Float64s(volumes)
+	var sum float64
+	for _, v := range volumes {
+		sum += v
+	}
+	return sum
+}
@@ -0,0 +1,68 @@
+// Package voronoi computes Voronoi diagrams of two dimensional point sets.
+//
+// The sites are lifted onto the paraboloid z = x² + y² and hulled with quickhull. The lower faces of the hull are the
+// Delaunay triangles, their circumcenters are the Voronoi vertices and the faces around a lifted site form its cell.
+package voronoi
+
+import (
+	"fmt"
+
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+	"github.com/markus-wa/quickhull-go/v2/internal/paraboloid"
+)
+
+// Diagram is a Voronoi diagram clipped to a bounding box.
+type Diagram struct {
+	// Cells[i] is the counter-clockwise polygon of the region closer to site i than to any other site.
+	// Empty if the region doesn't intersect the bounds or if the site (nearly) coincides with another site, which gets the region.
+	Cells [][]r2.Point
+}
+
+// Compute computes the Voronoi diagram of the sites, with cells clipped to bounds.
+// If epsilon is <= 0 a default value will be used, it's relative to the extent of the sites and bounds.
+// Returns an error of kind quickhull.KindInvalidArgument if bounds is empty.
+func Compute(sites []r2.Point, bounds r2.Rect, epsilon float64) (Diagram, error) {
+	if bounds.IsEmpty() {
+		return Diagram{}, &quickhull.Error{Kind: quickhull.KindInvalidArgument, Msg: fmt.Sprintf("bounds %v are empty", bounds)}
+	}
+	if len(sites) == 0 {
+		return Diagram{}, nil
+	}
+	if epsilon <= 0 {
+		epsilon = quickhull.DefaultEpsilon
+	}
+
+	// Surround everything with ghost sites, so the cells of all real sites are bounded
+	points := append(append([]r2.Point(nil), sites...), paraboloid.Ghosts(sites, bounds)...)
+
+	t, err := paraboloid.NewTransform(points)
+	if err != nil {
+		return Diagram{}, err
+	}
+
+	mesh, err := new(quickhull.QuickHull).TryConvexHullAsMesh(t.Lift(points, nil), quickhull.Options{
+		Epsilon:        epsilon,
+		IndexMode:      quickhull.OriginalIndices,
+		ComputeNormals: true,
+		Robust:         true,
+	})
+	if err != nil {
+		return Diagram{}, err
+	}
+
+	circumcenter := func(a, b, c int) r2.Point {
+		return Circumcenter(points[a], points[b], points[c])
+	}
+
+	return Diagram{Cells: paraboloid.Cells(mesh, len(sites), circumcenter, bounds)}, nil
+}
+
+// Circumcenter returns the center of the circle through a, b and c, which is the Voronoi vertex of the three sites.
+func Circumcenter(a, b, c r2.Point) r2.Point {
+	b, c = b.Sub(a), c.Sub(a)
+	d := 2 * b.Cross(c)
+	bb, cc := b.Dot(b), c.Dot(c)
+	return a.Add(r2.Point{X: (c.Y*bb - b.Y*cc) / d, Y: (b.X*cc - c.X*bb) / d})
+}
@@ -0,0 +1,139 @@
+package voronoi
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/r2"
+
+	quickhull "github.com/markus-wa/quickhull-go/v2"
+)
+
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func assertAlmostEqual(t *testing.T, expected, actual float64) {
+	t.Helper()
+
+	if math.Abs(expected-actual) > 1e-9 {
+		t.Errorf("assertion failed: %v != %v", expected, actual)
+	}
+}
+
+func area(polygon []r2.Point) float64 {
+	var a float64
+	for i, p := range polygon {
+		a += p.Cross(polygon[(i+1)%len(polygon)])
+	}
+	return a / 2
+}
+
+func contains(polygon []r2.Point, p r2.Point) bool {
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if b.Sub(a).Cross(p.Sub(a)) < -1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func unitSquare() r2.Rect {
+	return r2.Rect{X: r1.Interval{Lo: 0, Hi: 1}, Y: r1.Interval{Lo: 0, Hi: 1}}
+}
+
+func TestComputeQuadrants(t *testing.T) {
+	sites := []r2.Point{{X: 0.25, Y: 0.25}, {X: 0.75, Y: 0.25}, {X: 0.75, Y: 0.75}, {X: 0.25, Y: 0.75}}
+
+	diagram, err := Compute(sites, unitSquare(), 0)
+	assertEqual(t, nil, err)
+	assertEqual(t, len(sites), len(diagram.Cells))
+	for i, cell := range diagram.Cells {
+		assertEqual(t, 4, len(cell))
+		assertAlmostEqual(t, 0.25, area(cell))
+		assertEqual(t, true, contains(cell, sites[i]))
+	}
+}
+
+func TestComputeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sites := make([]r2.Point, 200)
+	for i := range sites {
+		sites[i] = r2.Point{X: r.Float64(), Y: r.Float64()}
+	}
+
+	diagram, err := Compute(sites, unitSquare(), 0)
+	assertEqual(t, nil, err)
+
+	var total float64
+	for _, cell := range diagram.Cells {
+		a := area(cell)
+		if a <= 0 {
+			t.Fatalf("cell %v isn't counter-clockwise", cell)
+		}
+		total += a
+	}
+	assertAlmostEqual(t, 1, total)
+
+	// Every point is in the cell of its nearest site
+	for k := 0; k < 1000; k++ {
+		p := r2.Point{X: r.Float64(), Y: r.Float64()}
+		nearest := 0
+		for i, s := range sites {
+			if s.Sub(p).Norm() < sites[nearest].Sub(p).Norm() {
+				nearest = i
+			}
+		}
+		if !contains(diagram.Cells[nearest], p) {
+			t.Fatalf("point %v isn't in the cell of its nearest site %v", p, sites[nearest])
+		}
+	}
+}
+
+func TestComputeGrid(t *testing.T) {
+	// All groups of four neighboring sites are cocircular
+	var sites []r2.Point
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			sites = append(sites, r2.Point{X: float64(x) + 0.5, Y: float64(y) + 0.5})
+		}
+	}
+
+	diagram, err := Compute(sites, r2.Rect{X: r1.Interval{Lo: 0, Hi: 5}, Y: r1.Interval{Lo: 0, Hi: 5}}, 0)
+	assertEqual(t, nil, err)
+	for i, cell := range diagram.Cells {
+		assertEqual(t, 4, len(cell))
+		assertAlmostEqual(t, 1, area(cell))
+		assertEqual(t, true, contains(cell, sites[i]))
+	}
+}
+
+func TestComputeDegenerate(t *testing.T) {
+	diagram, err := Compute([]r2.Point{{X: 0.5, Y: 0.5}}, unitSquare(), 0)
+	assertEqual(t, nil, err)
+	assertAlmostEqual(t, 1, area(diagram.Cells[0]))
+
+	// Collinear sites have parallel strips as cells
+	diagram, err = Compute([]r2.Point{{X: 0.25, Y: 0.5}, {X: 0.5, Y: 0.5}, {X: 0.75, Y: 0.5}}, unitSquare(), 0)
+	assertEqual(t, nil, err)
+	assertAlmostEqual(t, 0.375, area(diagram.Cells[0]))
+	assertAlmostEqual(t, 0.25, area(diagram.Cells[1]))
+	assertAlmostEqual(t, 0.375, area(diagram.Cells[2]))
+
+	// Duplicate sites share a cell
+	diagram, err = Compute([]r2.Point{{X: 0.25, Y: 0.5}, {X: 0.25, Y: 0.5}, {X: 0.75, Y: 0.5}}, unitSquare(), 0)
+	assertEqual(t, nil, err)
+	assertAlmostEqual(t, 0.5, area(diagram.Cells[0])+area(diagram.Cells[1]))
+	assertEqual(t, true, len(diagram.Cells[0]) == 0 || len(diagram.Cells[1]) == 0)
+
+	_, err = Compute([]r2.Point{{X: 0.5, Y: 0.5}}, r2.EmptyRect(), 0)
+	assertEqual(t, quickhull.KindInvalidArgument, err.(*quickhull.Error).Kind)
+}